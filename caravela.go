@@ -9,15 +9,48 @@ import (
 )
 
 // A Conf is a wrapper of data to be passed as input to the public functions.
+// The optional fields are those of updater.Options, where they are detailed.
 type Conf struct {
 	Version     string
 	Provider    pvdr.UpdaterProvider
 	HTTPClient  *http.Client
 	IgnoreCache bool
+
+	// RewriteURL, when set, is applied to every asset URL before it is downloaded.
+	RewriteURL func(url string) string
+	// VersionScheme, when set, takes precedence over the scheme of the provider.
+	VersionScheme pvdr.VersionScheme
+	// Channel tells which releases are offered, provider.Stable by default.
+	Channel pvdr.Channel
+	// Constraint, when set, is the range of versions updates stay within, e.g. ^2.
+	Constraint string
+	// TargetVersion, when set, is the exact version to move to.
+	TargetVersion string
+	// AllowDowngrade allows TargetVersion to be older than Version.
+	AllowDowngrade bool
+	// SkipVersions are yanked versions that are never offered.
+	SkipVersions []string
+	// BlocklistURL, when set, is the URL of a text file listing yanked versions.
+	BlocklistURL string
+	// InstallationID identifies this installation in staged rollouts.
+	InstallationID string
+	// MaxExtractedSize and MaxExtractedFiles bound the content extracted from an archive.
+	MaxExtractedSize  int64
+	MaxExtractedFiles int
+	// ChecksumsFile is the name or pattern of the checksums asset, checksums.txt by default.
+	ChecksumsFile string
+	// ChecksumAlgorithm is the hash function of checksums whose format doesn't tell it.
+	ChecksumAlgorithm caravela.ChecksumAlgorithm
+	// OnVerification, when set, is given the report of the checksum verification.
+	OnVerification func(report caravela.VerificationReport)
+	// SmokeTest, when set, runs the new executable before it replaces the running one.
+	SmokeTest *caravela.SmokeTest
 }
 
-var mpCheckForUpdates = caravela.FindUpdate
-var mpUpdate = caravela.UpdateRelease
+var mpCheckForUpdates = caravela.FindUpdateWithOptions
+var mpUpdate = caravela.UpdateReleaseWithOptions
+var mpListReleases = caravela.ListReleases
+var mpReleaseNotes = caravela.ReleaseNotes
 
 // CheckUpdates fetches the last release published.
 //
@@ -34,7 +67,7 @@ func CheckUpdates(c Conf) (*pvdr.Release, error) {
 
 	client := pvdr.HTTPClientDecorator{Client: *c.HTTPClient}
 
-	return mpCheckForUpdates(&client, c.Provider, c.Version, c.IgnoreCache)
+	return mpCheckForUpdates(&client, c.Provider, c.Version, c.IgnoreCache, buildOptions(c))
 }
 
 // Update updates running program to the last available release.
@@ -48,5 +81,59 @@ func Update(c Conf) (*pvdr.Release, error) {
 
 	client := pvdr.HTTPClientDecorator{Client: *c.HTTPClient}
 
-	return mpUpdate(&client, c.Provider, c.Version, c.IgnoreCache)
+	return mpUpdate(&client, c.Provider, c.Version, c.IgnoreCache, buildOptions(c))
+}
+
+// ListReleases fetches the releases published, e.g. to show a version picker.
+//
+// It returns the releases offered according to the channel and the constraint,
+// sorted by version, newest first. Pass the chosen one as Conf.TargetVersion to
+// Update in order to install it.
+func ListReleases(c Conf) ([]*pvdr.Release, error) {
+	if c.HTTPClient == nil {
+		c.HTTPClient = http.DefaultClient
+	}
+
+	client := pvdr.HTTPClientDecorator{Client: *c.HTTPClient}
+
+	return mpListReleases(&client, c.Provider, buildOptions(c))
+}
+
+// ReleaseNotes fetches the notes of every release newer than the current version
+// up to target, newest first. When target is empty, the last release is taken.
+//
+// It returns notes that render as Markdown or plain text, so users see every
+// change they are skipping over.
+func ReleaseNotes(c Conf, target string) (pvdr.ReleaseNotes, error) {
+	if c.Version == "" {
+		return nil, fmt.Errorf("current version is required")
+	}
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = http.DefaultClient
+	}
+
+	client := pvdr.HTTPClientDecorator{Client: *c.HTTPClient}
+
+	return mpReleaseNotes(&client, c.Provider, c.Version, target, buildOptions(c))
+}
+
+func buildOptions(c Conf) caravela.Options {
+	return caravela.Options{
+		RewriteURL:        c.RewriteURL,
+		Scheme:            c.VersionScheme,
+		Channel:           c.Channel,
+		Constraint:        c.Constraint,
+		TargetVersion:     c.TargetVersion,
+		AllowDowngrade:    c.AllowDowngrade,
+		SkipVersions:      c.SkipVersions,
+		BlocklistURL:      c.BlocklistURL,
+		InstallationID:    c.InstallationID,
+		MaxExtractedSize:  c.MaxExtractedSize,
+		MaxExtractedFiles: c.MaxExtractedFiles,
+		ChecksumsFile:     c.ChecksumsFile,
+		ChecksumAlgorithm: c.ChecksumAlgorithm,
+		OnVerification:    c.OnVerification,
+		SmokeTest:         c.SmokeTest,
+	}
 }
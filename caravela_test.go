@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	pvdr "github.com/aureliano/caravela/provider"
+	"github.com/aureliano/caravela/updater"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,7 +17,7 @@ func TestCheckForUpdatesCurrentVersionIsRequired(t *testing.T) {
 
 func TestCheckForUpdatesHTTPClientIsNil(t *testing.T) {
 	mpCheckForUpdates = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
-		currver string, ignoreCache bool) (*pvdr.Release, error) {
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
 		return nil, fmt.Errorf("already on the edge")
 	}
 
@@ -27,7 +28,7 @@ func TestCheckForUpdatesHTTPClientIsNil(t *testing.T) {
 
 func TestCheckForUpdates(t *testing.T) {
 	mpCheckForUpdates = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
-		currver string, ignoreCache bool) (*pvdr.Release, error) {
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
 		return nil, fmt.Errorf("already on the edge")
 	}
 
@@ -36,12 +37,181 @@ func TestCheckForUpdates(t *testing.T) {
 	assert.Equal(t, "already on the edge", err.Error())
 }
 
+func TestCheckForUpdatesVersionScheme(t *testing.T) {
+	mpCheckForUpdates = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, pvdr.CalVer{}, opts.Scheme)
+		return &pvdr.Release{Name: "2024.06.1"}, nil
+	}
+
+	r, err := CheckUpdates(Conf{Version: "2024.05.3", VersionScheme: pvdr.CalVer{}})
+	assert.Nil(t, err)
+	assert.Equal(t, "2024.06.1", r.Name)
+}
+
+func TestCheckForUpdatesChannel(t *testing.T) {
+	mpCheckForUpdates = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, pvdr.Beta, opts.Channel)
+		return &pvdr.Release{Name: "0.2.0-beta.1"}, nil
+	}
+
+	r, err := CheckUpdates(Conf{Version: "0.1.0", Channel: pvdr.Beta})
+	assert.Nil(t, err)
+	assert.Equal(t, "0.2.0-beta.1", r.Name)
+}
+
+func TestCheckForUpdatesMandatory(t *testing.T) {
+	mpCheckForUpdates = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		return &pvdr.Release{Name: "1.6.0", MinSupportedVersion: "1.5.2"}, nil
+	}
+
+	conf := Conf{Version: "1.2.0"}
+	r, err := CheckUpdates(conf)
+	assert.Nil(t, err)
+	assert.False(t, r.Critical)
+	assert.True(t, r.Mandatory(conf.Version, conf.VersionScheme))
+}
+
+func TestUpdateConstraint(t *testing.T) {
+	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, "^2", opts.Constraint)
+		return &pvdr.Release{Name: "2.5.1"}, nil
+	}
+
+	r, err := Update(Conf{Version: "2.4.0", Constraint: "^2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "2.5.1", r.Name)
+}
+
+func TestUpdateTargetVersion(t *testing.T) {
+	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, "1.8.3", opts.TargetVersion)
+		assert.True(t, opts.AllowDowngrade)
+		return &pvdr.Release{Name: opts.TargetVersion}, nil
+	}
+
+	r, err := Update(Conf{Version: "1.9.0", TargetVersion: "1.8.3", AllowDowngrade: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.8.3", r.Name)
+}
+
+func TestUpdateSkipVersions(t *testing.T) {
+	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, []string{"0.3.0"}, opts.SkipVersions)
+		assert.Equal(t, "https://dl.corp.com/blocklist.txt", opts.BlocklistURL)
+		assert.Equal(t, "machine-42", opts.InstallationID)
+		return &pvdr.Release{Name: "0.2.1"}, nil
+	}
+
+	r, err := Update(Conf{
+		Version:        "0.1.0",
+		SkipVersions:   []string{"0.3.0"},
+		BlocklistURL:   "https://dl.corp.com/blocklist.txt",
+		InstallationID: "machine-42",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "0.2.1", r.Name)
+}
+
+func TestUpdateExtractionLimits(t *testing.T) {
+	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, int64(1<<20), opts.MaxExtractedSize)
+		assert.Equal(t, 50, opts.MaxExtractedFiles)
+		return nil, &updater.ExtractionError{Entry: "../14-bis", Err: updater.ErrOutsideRoot}
+	}
+
+	_, err := Update(Conf{Version: "0.1.0", MaxExtractedSize: 1 << 20, MaxExtractedFiles: 50})
+	assert.ErrorIs(t, err, updater.ErrOutsideRoot)
+}
+
+func TestUpdateChecksumSettings(t *testing.T) {
+	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, "*_checksums.txt", opts.ChecksumsFile)
+		assert.Equal(t, updater.SHA512, opts.ChecksumAlgorithm)
+		opts.OnVerification(updater.VerificationReport{File: "14-bis.zip", Verified: true})
+		return &pvdr.Release{Name: "0.2.0"}, nil
+	}
+
+	var reports []updater.VerificationReport
+	r, err := Update(Conf{
+		Version:           "0.1.0",
+		ChecksumsFile:     "*_checksums.txt",
+		ChecksumAlgorithm: updater.SHA512,
+		OnVerification:    func(report updater.VerificationReport) { reports = append(reports, report) },
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "0.2.0", r.Name)
+	assert.Equal(t, []updater.VerificationReport{{File: "14-bis.zip", Verified: true}}, reports)
+}
+
+func TestUpdateSmokeTest(t *testing.T) {
+	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		assert.Equal(t, &updater.SmokeTest{Args: []string{"--version"}, ExpectVersion: true}, opts.SmokeTest)
+		return nil, fmt.Errorf("%w: exit status 1", updater.ErrSmokeTestFailed)
+	}
+
+	test := &updater.SmokeTest{Args: []string{"--version"}, ExpectVersion: true}
+	_, err := Update(Conf{Version: "0.1.0", SmokeTest: test})
+	assert.ErrorIs(t, err, updater.ErrSmokeTestFailed)
+}
+
+func TestListReleases(t *testing.T) {
+	mpListReleases = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		opts updater.Options) ([]*pvdr.Release, error) {
+		assert.Equal(t, pvdr.Beta, opts.Channel)
+		return []*pvdr.Release{{Name: "0.2.0-rc.1"}, {Name: "0.1.0"}}, nil
+	}
+
+	releases, err := ListReleases(Conf{Channel: pvdr.Beta})
+	assert.Nil(t, err)
+	assert.Len(t, releases, 2)
+}
+
+func TestReleaseNotesCurrentVersionIsRequired(t *testing.T) {
+	_, err := ReleaseNotes(Conf{}, "")
+	assert.Equal(t, "current version is required", err.Error())
+}
+
+func TestReleaseNotes(t *testing.T) {
+	mpReleaseNotes = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver, target string, opts updater.Options) (pvdr.ReleaseNotes, error) {
+		assert.Equal(t, "1.2.0", currver)
+		assert.Equal(t, "1.6.0", target)
+		return pvdr.ReleaseNotes{{Name: "1.6.0", Description: "Bug fix."}}, nil
+	}
+
+	notes, err := ReleaseNotes(Conf{Version: "1.2.0"}, "1.6.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "## 1.6.0\n\nBug fix.\n", notes.Markdown())
+}
+
 func TestUpdateHTTPClientIsNil(t *testing.T) {
 	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
-		currver string, ignoreCache bool) (*pvdr.Release, error) {
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
 		return nil, fmt.Errorf("")
 	}
 
 	_, err := Update(Conf{Version: "0.1.0"})
 	assert.NotNil(t, err)
 }
+
+func TestUpdateRewriteURL(t *testing.T) {
+	mpUpdate = func(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider,
+		currver string, ignoreCache bool, opts updater.Options) (*pvdr.Release, error) {
+		return &pvdr.Release{Name: opts.RewriteURL("https://github.com/file.zip")}, nil
+	}
+
+	r, err := Update(Conf{Version: "0.1.0", RewriteURL: pvdr.PrefixRewriter(map[string]string{
+		"https://github.com/": "https://artifactory.corp.com/",
+	})})
+	assert.Nil(t, err, err)
+	assert.Equal(t, "https://artifactory.corp.com/file.zip", r.Name)
+}
@@ -31,6 +31,34 @@ It returns the last release available or raises an error if the current version
 		fmt.Printf("New version available %s\n%s\n", release.Name, release.Description)
 	}
 
+# Mandatory updates
+
+A release may be flagged as critical, e.g. a security fix, or declare the minimum version still
+supported, so versions below 1.5.2 must update. The flags are read from a marker in the release
+description or, on OCI registries, from the io.caravela.critical and
+io.caravela.min-supported-version annotations. Sparkle appcasts use sparkle:criticalUpdate.
+
+	<!-- caravela
+	critical: true
+	min_supported_version: 1.5.2
+	-->
+
+Release.Mandatory tells whether the update found by CheckUpdates must be installed, so apps
+can enforce critical updates and just nag about the others.
+
+	if release.Mandatory(conf.Version, conf.VersionScheme) {
+		fmt.Println("This version is no longer supported, please update now.")
+	}
+
+Only the flags of the release found are considered. When several versions are skipped
+over, ReleaseNotes gathers every release in between and ReleaseNotes.Mandatory considers
+the flags of all of them.
+
+	notes, err := caravela.ReleaseNotes(conf, release.Name)
+	if err == nil && notes.Mandatory(conf.Version, conf.VersionScheme) {
+		fmt.Println("A critical update was released since this version, please update now.")
+	}
+
 # Update
 
 Update updates running program to the last available release.
@@ -52,6 +80,120 @@ It returns the release used to update this program or raises an error if it's al
 		fmt.Println("New version installed!")
 	}
 
+# Smoke test
+
+SmokeTest runs the new executable before it replaces the running one, so a broken
+build is never installed. The update is aborted unless it exits successfully within
+the timeout and, with ExpectVersion, prints the version of the release.
+
+	release, err := caravela.Update(caravela.Conf{
+		Version:   "0.1.0",
+		Provider:  provider.GithubProvider{Host: "api.github.com", Ssl: true, ProjectPath: "owner/project"},
+		SmokeTest: &updater.SmokeTest{Args: []string{"--version"}, Timeout: 5 * time.Second, ExpectVersion: true},
+	})
+
+# Version schemes
+
+Releases are compared according to Semantic Versioning 2.0.0 by default. Projects that
+use calendar versions or build numbers set another scheme, either on the provider or
+on Conf.
+
+	release, err := caravela.CheckUpdates(caravela.Conf{
+		Version:       "2024.06.1",
+		VersionScheme: provider.CalVer{},
+		Provider: provider.GithubProvider{
+			Host:        "api.github.com",
+			Ssl:         true,
+			ProjectPath: "owner/project",
+		},
+	})
+
+# Release channels
+
+By default, only final releases are offered. Users who opt in get pre-releases too:
+provider.Beta offers beta and release candidate builds (e.g. 2.0.0-beta.1 or 2.0.0-rc.2),
+while provider.Nightly offers any release. Drafts are never offered.
+
+	release, err := caravela.CheckUpdates(caravela.Conf{
+		Version:  "0.1.0",
+		Channel:  provider.Beta,
+		Provider: provider.GithubProvider{Host: "api.github.com", Ssl: true, ProjectPath: "owner/project"},
+	})
+
+# Version constraints
+
+Constraint keeps updates within a range of semantic versions, so customers on 2.x are
+never moved to 3.x: ^2 stays on 2.x, ~2.4 stays on 2.4.x and >=2.3 <3 is an explicit
+range. The highest release satisfying the constraint is taken.
+
+	release, err := caravela.Update(caravela.Conf{
+		Version:    "2.4.0",
+		Constraint: "^2",
+		Provider:   provider.GithubProvider{Host: "api.github.com", Ssl: true, ProjectPath: "owner/project"},
+	})
+
+# Yanked releases
+
+After a bad release, e.g. one whose assets have been deleted, its version can be yanked
+through SkipVersions or a blocklist, a text file with a version per line. Yanked versions
+are never offered and the next best release is taken in their place.
+
+	release, err := caravela.Update(caravela.Conf{
+		Version:      "1.2.0",
+		Provider:     provider.GithubProvider{Host: "api.github.com", Ssl: true, ProjectPath: "owner/project"},
+		SkipVersions: []string{"1.4.0"},
+		BlocklistURL: "https://dl.corp.com/project/blocklist.txt",
+	})
+
+# Staged rollouts
+
+A release may reach 5%, then 25%, then 100% of installations. The percentage is read from
+a rollout field in the marker of the release description or, on OCI registries, from the
+io.caravela.rollout annotation. Installations that are not within the rollout yet are
+offered the previous release as the last one. A rollout of 0% pauses it, so the release is
+offered to no installation.
+
+	<!-- caravela: rollout: 25% -->
+
+Each installation is hashed into a bucket using InstallationID, when set, or an identifier
+generated once and kept in the user configuration directory.
+
+# Install a specific version
+
+TargetVersion moves the program to an exact version instead of the last release, e.g. to
+pin a known good version during an incident. It goes through the same download, checksum
+and installation steps. Moving to an older version must be explicitly allowed.
+
+	release, err := caravela.Update(caravela.Conf{
+		Version:        "1.9.0",
+		TargetVersion:  "1.8.3",
+		AllowDowngrade: true,
+		Provider:       provider.GithubProvider{Host: "api.github.com", Ssl: true, ProjectPath: "owner/project"},
+	})
+
+# List releases
+
+ListReleases returns the releases offered according to the channel and the constraint,
+newest first, e.g. to show a version picker. The version picked is then installed with
+TargetVersion.
+
+	releases, err := caravela.ListReleases(caravela.Conf{
+		Provider: provider.GithubProvider{Host: "api.github.com", Ssl: true, ProjectPath: "owner/project"},
+	})
+
+# Release notes
+
+ReleaseNotes gathers the notes of every release between the current version and a target
+one (the last release when empty), newest first, so users jumping from 1.2.0 to 1.6.0 see
+every breaking change they are skipping over. They render as Markdown or plain text.
+
+	notes, err := caravela.ReleaseNotes(caravela.Conf{
+		Version:  "1.2.0",
+		Provider: provider.GithubProvider{Host: "api.github.com", Ssl: true, ProjectPath: "owner/project"},
+	}, "")
+
+	fmt.Println(notes.Text())
+
 # Put it all together
 
 Let's put it all together chainning CheckUpdates and Update.
@@ -3,13 +3,19 @@ module github.com/aureliano/caravela
 go 1.19
 
 require (
+	github.com/klauspost/compress v1.16.7
 	github.com/stretchr/testify v1.8.2
-	golang.org/x/text v0.8.0
+	github.com/ulikunitz/xz v0.5.12
+	github.com/zeebo/blake3 v0.2.3
+	golang.org/x/crypto v0.14.0
+	golang.org/x/text v0.13.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/klauspost/cpuid/v2 v2.0.12 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// AppcastProvider is a provider for getting releases from a Sparkle appcast feed.
+//
+// Items sharing the same version are merged into a single release, whose assets
+// are tagged with the platform given by sparkle:os. Items published on a
+// sparkle:channel are pre-releases. PublicKey is the base64 encoded ed25519
+// key used to check the sparkle:edSignature of the assets.
+type AppcastProvider struct {
+	URL       string
+	PublicKey string
+	Timeout   time.Duration
+	Scheme    VersionScheme
+}
+
+// Appcast is a representation - in XML form - of a Sparkle appcast feed.
+type Appcast struct {
+	Items []AppcastItem `xml:"channel>item"`
+}
+
+// AppcastItem is a representation - in XML form - of an item of a Sparkle appcast feed.
+type AppcastItem struct {
+	Title              string `xml:"title"`
+	Description        string `xml:"description"`
+	PubDate            string `xml:"pubDate"`
+	Version            string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle version"`
+	ShortVersionString string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle shortVersionString"`
+	Channel            string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle channel"`
+	CriticalUpdate     *struct {
+		Version string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle version,attr"`
+	} `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle criticalUpdate"`
+	Enclosure struct {
+		URL                string `xml:"url,attr"`
+		Length             int64  `xml:"length,attr"`
+		Type               string `xml:"type,attr"`
+		Version            string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle version,attr"`
+		ShortVersionString string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle shortVersionString,attr"`
+		EdSignature        string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle edSignature,attr"`
+		OS                 string `xml:"http://www.andymatuschak.org/xml-namespaces/sparkle os,attr"`
+	} `xml:"enclosure"`
+}
+
+func (provider AppcastProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
+	releases, err := provider.FetchReleases(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return LatestRelease(releases, provider.Scheme), nil
+}
+
+func (provider AppcastProvider) FetchReleases(client HTTPClientPlugin) ([]*Release, error) {
+	initAppcastProvider(&provider)
+	err := validateAppcastProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchAppcastReleases(provider, client)
+}
+
+func (provider AppcastProvider) VersionScheme() VersionScheme {
+	return schemeOrDefault(provider.Scheme)
+}
+
+func (AppcastProvider) CacheRelease(r Release) error {
+	return serializeRelease(&r)
+}
+
+func (AppcastProvider) RestoreCacheRelease() (*Release, error) {
+	return deserializeRelease()
+}
+
+// VerifyAsset checks the ed25519 signature and the length of a downloaded
+// asset against what the appcast feed announced.
+func (provider AppcastProvider) VerifyAsset(asset Asset, file string) error {
+	if provider.PublicKey == "" {
+		return fmt.Errorf("public key is required to verify %s", asset.Name)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(provider.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid ed25519 public key")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(asset.Signature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid ed25519 signature of %s", asset.Name)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if asset.Size > 0 && int64(len(data)) != asset.Size {
+		return fmt.Errorf("%s has %d bytes but %d were expected", asset.Name, len(data), asset.Size)
+	}
+
+	if !ed25519.Verify(key, data, signature) {
+		return fmt.Errorf("signature verification of %s failed", asset.Name)
+	}
+
+	return nil
+}
+
+func fetchAppcastReleases(p AppcastProvider, client HTTPClientPlugin) ([]*Release, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("appcast integration error: %d", resp.StatusCode)
+	}
+
+	appcast := &Appcast{}
+	err = xml.NewDecoder(resp.Body).Decode(appcast)
+
+	return convertAppcastReleases(appcast.Items), err
+}
+
+// convertAppcastReleases groups the appcast items by version, so a release
+// holds the assets of every platform it was published for.
+func convertAppcastReleases(items []AppcastItem) []*Release {
+	var rels []*Release
+	byVersion := make(map[string]*Release)
+
+	for i := range items {
+		item := &items[i]
+		version := appcastItemVersion(item)
+		if version == "" || item.Enclosure.URL == "" {
+			continue
+		}
+
+		release, found := byVersion[version]
+		if !found {
+			release = convertAppcastToBase(item)
+			release.Name = version
+			byVersion[version] = release
+			rels = append(rels, release)
+		}
+
+		release.Assets = append(release.Assets, convertAppcastEnclosure(item))
+	}
+
+	return rels
+}
+
+func convertAppcastToBase(item *AppcastItem) *Release {
+	t := Release{
+		Description: strings.TrimSpace(item.Description),
+		Prerelease:  strings.TrimSpace(item.Channel) != "",
+		Assets:      []Asset{},
+	}
+
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123} {
+		if date, err := time.Parse(layout, strings.TrimSpace(item.PubDate)); err == nil {
+			t.ReleasedAt = date
+			break
+		}
+	}
+
+	applyReleaseMetadata(&t)
+	if item.CriticalUpdate != nil {
+		// A sparkle:version attribute makes the update critical only for older versions.
+		if version := strings.TrimSpace(item.CriticalUpdate.Version); version != "" {
+			t.MinSupportedVersion = version
+		} else {
+			t.Critical = true
+		}
+	}
+
+	return &t
+}
+
+func convertAppcastEnclosure(item *AppcastItem) Asset {
+	enclosure := item.Enclosure
+	name := enclosure.URL
+	if u, err := url.Parse(enclosure.URL); err == nil {
+		name = path.Base(u.Path)
+	}
+
+	goos, goarch := convertSparkleOS(enclosure.OS)
+
+	return Asset{
+		Name:      name,
+		URL:       enclosure.URL,
+		Size:      enclosure.Length,
+		Signature: enclosure.EdSignature,
+		OS:        goos,
+		Arch:      goarch,
+	}
+}
+
+// appcastItemVersion returns the human readable version of an item, which is
+// the one compared against the running program version.
+func appcastItemVersion(item *AppcastItem) string {
+	candidates := []string{
+		item.ShortVersionString,
+		item.Enclosure.ShortVersionString,
+		item.Version,
+		item.Enclosure.Version,
+	}
+
+	for _, candidate := range candidates {
+		if candidate = strings.TrimSpace(candidate); candidate != "" {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// convertSparkleOS maps a sparkle:os value to GOOS and GOARCH.
+// Sparkle assumes macOS when no operating system is given.
+func convertSparkleOS(sparkleOS string) (string, string) {
+	switch strings.ToLower(sparkleOS) {
+	case "", "macos", "osx":
+		return "darwin", ""
+	case "windows-x86":
+		return "windows", "386"
+	case "windows-x64":
+		return "windows", "amd64"
+	case "windows-arm64":
+		return "windows", "arm64"
+	default:
+		parts := strings.SplitN(strings.ToLower(sparkleOS), "-", 2)
+		if len(parts) == 2 {
+			return parts[0], parts[1]
+		}
+		return parts[0], ""
+	}
+}
+
+func validateAppcastProvider(p AppcastProvider) error {
+	if p.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	return nil
+}
+
+func initAppcastProvider(p *AppcastProvider) {
+	const timeout = time.Second * 30
+
+	if p.Timeout == 0 {
+		p.Timeout = timeout
+	}
+}
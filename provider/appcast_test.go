@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const appcastFeed = `<?xml version="1.0" encoding="utf-8"?>
+<rss version="2.0" xmlns:sparkle="http://www.andymatuschak.org/xml-namespaces/sparkle">
+	<channel>
+		<title>14-bis</title>
+		<item>
+			<title>Version 0.1.10</title>
+			<description><![CDATA[<p>Bug fix.</p>]]></description>
+			<pubDate>Thu, 09 Mar 2023 14:11:18 +0000</pubDate>
+			<sparkle:version>110</sparkle:version>
+			<sparkle:shortVersionString>0.1.10</sparkle:shortVersionString>
+			<enclosure url="https://dl.corp.com/14-bis_0.1.10_mac.zip" length="3" type="application/octet-stream"
+				sparkle:edSignature="c2lnbmF0dXJl" />
+		</item>
+		<item>
+			<title>Version 0.1.10</title>
+			<pubDate>Thu, 09 Mar 2023 14:11:18 +0000</pubDate>
+			<sparkle:version>110</sparkle:version>
+			<sparkle:shortVersionString>0.1.10</sparkle:shortVersionString>
+			<enclosure url="https://dl.corp.com/14-bis_0.1.10_win.zip?token=1" length="4"
+				sparkle:os="windows-x64" sparkle:edSignature="c2lnbmF0dXJl" />
+		</item>
+		<item>
+			<title>Version 0.1.2</title>
+			<pubDate>Mon, 06 Mar 2023 09:59:26 +0000</pubDate>
+			<enclosure url="https://dl.corp.com/14-bis_0.1.2_linux.tar.gz" length="5" sparkle:os="linux"
+				sparkle:version="102" sparkle:shortVersionString="0.1.2" />
+		</item>
+		<item>
+			<title>No enclosure</title>
+			<sparkle:version>200</sparkle:version>
+		</item>
+	</channel>
+</rss>`
+
+func TestAppcastFetchLastReleaseValidationError(t *testing.T) {
+	m := new(mockDecorator)
+	p := AppcastProvider{}
+
+	_, err := p.FetchLastRelease(m)
+	assert.Equal(t, "url is required", err.Error())
+}
+
+func TestAppcastFetchLastReleaseErrorOnFetchReleases(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewReader([]byte(``))),
+		}, nil)
+
+	p := AppcastProvider{URL: "https://dl.corp.com/appcast.xml"}
+	_, err := p.FetchLastRelease(m)
+	assert.Equal(t, "appcast integration error: 404", err.Error())
+}
+
+func TestAppcastFetchLastRelease(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(appcastFeed))),
+		}, nil)
+
+	p := AppcastProvider{URL: "https://dl.corp.com/appcast.xml"}
+	actual, err := p.FetchLastRelease(m)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, "0.1.10", actual.Name)
+	assert.Equal(t, "<p>Bug fix.</p>", actual.Description)
+	assert.Equal(t, time.Date(2023, 3, 9, 14, 11, 18, 0, time.UTC), actual.ReleasedAt.UTC())
+	assert.Equal(t, []Asset{
+		{
+			Name: "14-bis_0.1.10_mac.zip", URL: "https://dl.corp.com/14-bis_0.1.10_mac.zip",
+			Size: 3, OS: "darwin", Signature: "c2lnbmF0dXJl",
+		},
+		{
+			Name: "14-bis_0.1.10_win.zip", URL: "https://dl.corp.com/14-bis_0.1.10_win.zip?token=1",
+			Size: 4, OS: "windows", Arch: "amd64", Signature: "c2lnbmF0dXJl",
+		},
+	}, actual.Assets)
+}
+
+func TestAppcastFetchReleasesBrokenXML(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`<rss><channel><item>`))),
+		}, nil)
+
+	p := AppcastProvider{URL: "https://dl.corp.com/appcast.xml"}
+	_, err := fetchAppcastReleases(p, m)
+	assert.NotNil(t, err)
+}
+
+func TestConvertAppcastReleases(t *testing.T) {
+	appcast := &Appcast{}
+	err := xml.Unmarshal([]byte(appcastFeed), appcast)
+	assert.Nil(t, err, err)
+
+	actual := convertAppcastReleases(appcast.Items)
+	assert.Len(t, actual, 2)
+	assert.Equal(t, "0.1.2", actual[1].Name)
+	assert.Equal(t, []Asset{{
+		Name: "14-bis_0.1.2_linux.tar.gz", URL: "https://dl.corp.com/14-bis_0.1.2_linux.tar.gz", Size: 5, OS: "linux",
+	}}, actual[1].Assets)
+}
+
+func TestConvertAppcastReleasesChannel(t *testing.T) {
+	items := []AppcastItem{{ShortVersionString: "0.2.0", Channel: "beta"}, {ShortVersionString: "0.1.10"}}
+	items[0].Enclosure.URL = "https://dl.corp.com/14-bis_0.2.0_mac.zip"
+	items[1].Enclosure.URL = "https://dl.corp.com/14-bis_0.1.10_mac.zip"
+
+	actual := convertAppcastReleases(items)
+	assert.True(t, actual[0].Prerelease)
+	assert.False(t, actual[1].Prerelease)
+}
+
+func TestConvertAppcastReleasesCriticalUpdate(t *testing.T) {
+	feed := `<rss xmlns:sparkle="http://www.andymatuschak.org/xml-namespaces/sparkle"><channel>
+		<item>
+			<sparkle:version>0.2.0</sparkle:version>
+			<sparkle:criticalUpdate sparkle:version="0.1.5" />
+			<enclosure url="https://dl.corp.com/14-bis_0.2.0_mac.zip" />
+		</item>
+		<item>
+			<sparkle:version>0.1.10</sparkle:version>
+			<sparkle:criticalUpdate />
+			<enclosure url="https://dl.corp.com/14-bis_0.1.10_mac.zip" />
+		</item>
+		<item>
+			<sparkle:version>0.1.2</sparkle:version>
+			<enclosure url="https://dl.corp.com/14-bis_0.1.2_mac.zip" />
+		</item>
+	</channel></rss>`
+
+	appcast := &Appcast{}
+	err := xml.Unmarshal([]byte(feed), appcast)
+	assert.Nil(t, err, err)
+
+	actual := convertAppcastReleases(appcast.Items)
+	assert.False(t, actual[0].Critical)
+	assert.Equal(t, "0.1.5", actual[0].MinSupportedVersion)
+	assert.True(t, actual[1].Critical)
+	assert.Empty(t, actual[1].MinSupportedVersion)
+	assert.False(t, actual[2].Critical)
+	assert.Empty(t, actual[2].MinSupportedVersion)
+}
+
+func TestConvertSparkleOS(t *testing.T) {
+	type testCase struct {
+		input    string
+		expected []string
+	}
+	testCases := []testCase{
+		{input: "", expected: []string{"darwin", ""}},
+		{input: "macos", expected: []string{"darwin", ""}},
+		{input: "windows", expected: []string{"windows", ""}},
+		{input: "windows-x86", expected: []string{"windows", "386"}},
+		{input: "windows-x64", expected: []string{"windows", "amd64"}},
+		{input: "windows-arm64", expected: []string{"windows", "arm64"}},
+		{input: "linux-arm64", expected: []string{"linux", "arm64"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			goos, goarch := convertSparkleOS(tc.input)
+			assert.Equal(t, tc.expected, []string{goos, goarch})
+		})
+	}
+}
+
+func TestAppcastVerifyAsset(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	file := filepath.Join(os.TempDir(), "14-bis_0.1.10_mac.zip")
+	_ = os.WriteFile(file, []byte("bin"), 0600)
+	defer os.Remove(file)
+
+	p := AppcastProvider{PublicKey: base64.StdEncoding.EncodeToString(pub)}
+	asset := Asset{
+		Name:      "14-bis_0.1.10_mac.zip",
+		Size:      3,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("bin"))),
+	}
+	assert.Nil(t, p.VerifyAsset(asset, file))
+
+	tampered := asset
+	tampered.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("other")))
+	assert.Equal(t, "signature verification of 14-bis_0.1.10_mac.zip failed", p.VerifyAsset(tampered, file).Error())
+
+	truncated := asset
+	truncated.Size = 10
+	assert.Equal(t, "14-bis_0.1.10_mac.zip has 3 bytes but 10 were expected", p.VerifyAsset(truncated, file).Error())
+
+	unsigned := asset
+	unsigned.Signature = ""
+	assert.Equal(t, "invalid ed25519 signature of 14-bis_0.1.10_mac.zip", p.VerifyAsset(unsigned, file).Error())
+
+	assert.Equal(t, "public key is required to verify 14-bis_0.1.10_mac.zip",
+		AppcastProvider{}.VerifyAsset(asset, file).Error())
+	assert.Equal(t, "invalid ed25519 public key", AppcastProvider{PublicKey: "a2V5"}.VerifyAsset(asset, file).Error())
+}
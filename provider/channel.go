@@ -0,0 +1,85 @@
+package provider
+
+import "strings"
+
+// Channel tells which releases a user is willing to receive.
+type Channel int
+
+const (
+	// Stable only receives final releases.
+	Stable Channel = iota
+	// Beta receives final releases plus beta and release candidate builds, e.g. 2.0.0-beta.1 or 2.0.0-rc.1.
+	Beta
+	// Nightly receives any release.
+	Nightly
+)
+
+var betaLabels = []string{"beta", "rc"}
+
+// Accepts tells whether release is offered to the users of the channel. A release is
+// a pre-release when it is flagged as such by the provider or when its version, parsed
+// according to scheme, has pre-release identifiers. Drafts are never accepted.
+func (c Channel) Accepts(release *Release, scheme VersionScheme) bool {
+	if release.Draft {
+		return false
+	}
+
+	label, prerelease := prereleaseLabel(release.Name, scheme)
+	prerelease = prerelease || release.Prerelease
+
+	switch {
+	case c == Nightly || !prerelease:
+		return true
+	case c == Beta:
+		return label == "" || isBetaLabel(label)
+	default:
+		return false
+	}
+}
+
+func (c Channel) String() string {
+	switch c {
+	case Stable:
+		return "stable"
+	case Beta:
+		return "beta"
+	case Nightly:
+		return "nightly"
+	default:
+		return "unknown"
+	}
+}
+
+// FilterChannel returns the releases accepted by channel.
+func FilterChannel(releases []*Release, channel Channel, scheme VersionScheme) []*Release {
+	filtered := make([]*Release, 0, len(releases))
+	for _, release := range releases {
+		if channel.Accepts(release, scheme) {
+			filtered = append(filtered, release)
+		}
+	}
+
+	return filtered
+}
+
+// prereleaseLabel returns the first pre-release identifier of version and whether
+// it has any, as told by scheme.
+func prereleaseLabel(version string, scheme VersionScheme) (string, bool) {
+	prerelease := schemeOrDefault(scheme).Prerelease(version)
+	if prerelease == "" {
+		return "", false
+	}
+
+	return strings.SplitN(prerelease, ".", 2)[0], true
+}
+
+func isBetaLabel(label string) bool {
+	label = strings.ToLower(label)
+	for _, beta := range betaLabels {
+		if strings.HasPrefix(label, beta) {
+			return true
+		}
+	}
+
+	return false
+}
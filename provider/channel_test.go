@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildScheme is a custom scheme of build numbers whose pre-releases carry a
+// channel suffix, e.g. 42+beta.
+type buildScheme struct{ Monotonic }
+
+func (buildScheme) Prerelease(version string) string {
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		return version[i+1:]
+	}
+
+	return ""
+}
+
+func TestChannelAccepts(t *testing.T) {
+	type testCase struct {
+		name     string
+		release  Release
+		scheme   VersionScheme
+		expected []bool
+	}
+	testCases := []testCase{
+		{
+			name:     "final release",
+			release:  Release{Name: "v1.0.0"},
+			expected: []bool{true, true, true},
+		},
+		{
+			name:     "beta release",
+			release:  Release{Name: "v1.0.0-beta.1"},
+			expected: []bool{false, true, true},
+		},
+		{
+			name:     "release candidate",
+			release:  Release{Name: "v1.0.0-RC1"},
+			expected: []bool{false, true, true},
+		},
+		{
+			name:     "alpha release",
+			release:  Release{Name: "v1.0.0-alpha.1"},
+			expected: []bool{false, false, true},
+		},
+		{
+			name:     "nightly build",
+			release:  Release{Name: "v1.0.0-nightly.20240601"},
+			expected: []bool{false, false, true},
+		},
+		{
+			name:     "flagged as pre-release",
+			release:  Release{Name: "v1.0.0", Prerelease: true},
+			expected: []bool{false, true, true},
+		},
+		{
+			name:     "draft",
+			release:  Release{Name: "v1.0.0", Draft: true},
+			expected: []bool{false, false, false},
+		},
+		{
+			name:     "calendar version with modifier",
+			release:  Release{Name: "2024.06.1-beta"},
+			scheme:   CalVer{},
+			expected: []bool{false, true, true},
+		},
+		{
+			name:     "custom scheme pre-release",
+			release:  Release{Name: "42+beta"},
+			scheme:   buildScheme{},
+			expected: []bool{false, true, true},
+		},
+		{
+			name:     "custom scheme final release",
+			release:  Release{Name: "42"},
+			scheme:   buildScheme{},
+			expected: []bool{true, true, true},
+		},
+		{
+			name:     "not a version of the scheme",
+			release:  Release{Name: "latest"},
+			expected: []bool{true, true, true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, channel := range []Channel{Stable, Beta, Nightly} {
+				assert.Equal(t, tc.expected[i], channel.Accepts(&tc.release, tc.scheme), channel.String())
+			}
+		})
+	}
+}
+
+func TestChannelString(t *testing.T) {
+	assert.Equal(t, "stable", Stable.String())
+	assert.Equal(t, "beta", Beta.String())
+	assert.Equal(t, "nightly", Nightly.String())
+	assert.Equal(t, "unknown", Channel(9).String())
+}
+
+func TestFilterChannel(t *testing.T) {
+	releases := []*Release{{Name: "v1.0.0"}, {Name: "v1.1.0-rc.1"}, {Name: "v1.1.0-alpha"}, {Name: "v0.9.0", Draft: true}}
+
+	assert.Equal(t, []*Release{{Name: "v1.0.0"}}, FilterChannel(releases, Stable, nil))
+	assert.Equal(t, []*Release{{Name: "v1.0.0"}, {Name: "v1.1.0-rc.1"}}, FilterChannel(releases, Beta, nil))
+	assert.Len(t, FilterChannel(releases, Nightly, nil), 3)
+}
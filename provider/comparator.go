@@ -1,59 +1,31 @@
 package provider
 
-import (
-	"regexp"
-	"strconv"
-	"strings"
-)
-
-var releaseRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)-?([\w.]+)?$`)
+import "strings"
 
+// compareVersions compares two release names. Names that are semantic versions
+// are compared following Semantic Versioning 2.0.0 precedence rules, otherwise
+// they are compared lexically.
 func compareVersions(ver1, ver2 string) int {
-	match1 := releaseRegex.FindAllStringSubmatch(ver1, -1)
-	match2 := releaseRegex.FindAllStringSubmatch(ver2, -1)
-
-	if match1 == nil || match2 == nil {
+	c, err := CompareVersions(ver1, ver2)
+	if err != nil {
 		return strings.Compare(ver1, ver2)
 	}
 
-	major := compareVersionParts(match1[0][1], match2[0][1])
-	if major != 0 {
-		return major
-	}
-
-	minor := compareVersionParts(match1[0][2], match2[0][2])
-	if minor != 0 {
-		return minor
-	}
-
-	patch := compareVersionParts(match1[0][3], match2[0][3])
-	if patch != 0 {
-		return patch
-	}
-
-	pr1 := match1[0][4]
-	pr2 := match2[0][4]
-
-	switch {
-	case pr1 != "" && pr2 == "":
-		return -1
-	case pr1 == "" && pr2 != "":
-		return 1
-	default:
-		return strings.Compare(match1[0][4], match2[0][4])
-	}
+	return c
 }
 
+// compareVersionParts compares two numeric identifiers. They are compared by
+// length and then by digits, so identifiers of any size are supported.
 func compareVersionParts(v1, v2 string) int {
-	iv1, _ := strconv.Atoi(v1)
-	iv2, _ := strconv.Atoi(v2)
+	v1 = strings.TrimLeft(v1, "0")
+	v2 = strings.TrimLeft(v2, "0")
 
 	switch {
-	case iv1 > iv2:
+	case len(v1) > len(v2):
 		return 1
-	case iv1 == iv2:
-		return 0
-	default:
+	case len(v1) < len(v2):
 		return -1
+	default:
+		return strings.Compare(v1, v2)
 	}
 }
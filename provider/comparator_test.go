@@ -24,6 +24,16 @@ func TestCompareVersionParts(t *testing.T) {
 			input:    []string{"0", "1"},
 			expected: -1,
 		},
+		{
+			name:     "v1 has more digits than v2",
+			input:    []string{"10", "9"},
+			expected: 1,
+		},
+		{
+			name:     "v1 and v2 overflow int64",
+			input:    []string{"99999999999999999999", "100000000000000000000"},
+			expected: -1,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -103,6 +113,26 @@ func TestCompareVersions(t *testing.T) {
 			input:    []string{"v0.1.0-beta.1", "v0.1.0-beta.1"},
 			expected: 0,
 		},
+		{
+			name:     "r1 pre-release number is greater than r2 pre-release number",
+			input:    []string{"v1.0.0-rc.10", "v1.0.0-rc.2"},
+			expected: 1,
+		},
+		{
+			name:     "r1 has fewer pre-release identifiers than r2",
+			input:    []string{"v1.0.0-alpha", "v1.0.0-alpha.1"},
+			expected: -1,
+		},
+		{
+			name:     "r1 and r2 differ only in build metadata",
+			input:    []string{"v1.0.0+build.5", "v1.0.0+build.6"},
+			expected: 0,
+		},
+		{
+			name:     "r1 has build metadata and is greater than r2",
+			input:    []string{"v1.0.1+build.5", "v1.0.0"},
+			expected: 1,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a range of semantic versions, e.g. ^2, ~2.4 or >=2.3 <3.
+//
+// Comparators separated by spaces or commas must all be satisfied, while sets
+// of comparators separated by || are alternatives. Besides the usual =, !=, >,
+// >=, < and <= operators, ^ allows changes that do not modify the left-most
+// non-zero number (^2.4 is >=2.4.0 <3.0.0) and ~ allows patch changes when a
+// minor version is given (~2.4 is >=2.4.0 <2.5.0). Partial versions, such as 2
+// or 2.4.x, stand for every version they prefix.
+//
+// An upper bound never admits the pre-releases of its version, so <3 or ^2 are
+// not satisfied by 3.0.0-beta.1.
+type Constraint struct {
+	raw  string
+	sets [][]versionComparator
+}
+
+type versionComparator struct {
+	op      string
+	version Version
+}
+
+// partialVersion is a version whose trailing numbers may be missing or wildcards.
+type partialVersion struct {
+	version Version
+	size    int
+}
+
+var constraintOperators = []string{">=", "<=", "!=", ">", "<", "=", "^", "~"}
+
+// ParseConstraint parses a version constraint. It returns an error describing
+// why the constraint is not valid, if so.
+func ParseConstraint(constraint string) (Constraint, error) {
+	c := Constraint{raw: constraint}
+
+	for _, set := range strings.Split(constraint, "||") {
+		tokens, err := tokenizeConstraint(set)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", constraint, err)
+		} else if len(tokens) == 0 {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: empty range", constraint)
+		}
+
+		comparators := []versionComparator{}
+		for _, token := range tokens {
+			parsed, err := parseComparator(token)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("invalid constraint %q: %w", constraint, err)
+			}
+			comparators = append(comparators, parsed...)
+		}
+
+		c.sets = append(c.sets, comparators)
+	}
+
+	return c, nil
+}
+
+// Check tells whether version satisfies the constraint.
+func (c Constraint) Check(version Version) bool {
+	for _, set := range c.sets {
+		satisfied := true
+		for _, comparator := range set {
+			if !comparator.check(version) {
+				satisfied = false
+				break
+			}
+		}
+
+		if satisfied {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allows tells whether version is a semantic version that satisfies the constraint.
+func (c Constraint) Allows(version string) bool {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+
+	return c.Check(v)
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+func (vc versionComparator) check(version Version) bool {
+	bound := vc.version
+	if vc.op == "<" && !bound.IsPrerelease() {
+		// Lowest possible pre-release, so that <3.0.0 excludes 3.0.0-beta.1.
+		bound.Prerelease = []string{"0"}
+	}
+
+	c := version.Compare(bound)
+
+	switch vc.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+// tokenizeConstraint splits a set of comparators, joining operators that are
+// separated from their versions, e.g. ">= 2.3".
+func tokenizeConstraint(set string) ([]string, error) {
+	fields := strings.FieldsFunc(set, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	})
+
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		token := fields[i]
+		if isConstraintOperator(token) {
+			if i+1 == len(fields) {
+				return nil, fmt.Errorf("operator %s without version", token)
+			}
+			i++
+			token += fields[i]
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+func isConstraintOperator(token string) bool {
+	for _, op := range constraintOperators {
+		if token == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseComparator(token string) ([]versionComparator, error) {
+	op := ""
+	for _, candidate := range constraintOperators {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	pv, err := parsePartialVersion(strings.TrimPrefix(token, op))
+	if err != nil {
+		return nil, err
+	}
+
+	lower := pv.version
+	switch op {
+	case "", "=":
+		if pv.size == 0 {
+			return nil, nil
+		} else if pv.size == 3 {
+			return []versionComparator{{"=", lower}}, nil
+		}
+		return []versionComparator{{">=", lower}, {"<", pv.bump(pv.size - 1)}}, nil
+	case "!=":
+		if pv.size != 3 {
+			return nil, fmt.Errorf("%s requires a complete version", token)
+		}
+		return []versionComparator{{"!=", lower}}, nil
+	case ">":
+		if pv.size == 0 {
+			return nil, fmt.Errorf("%s is never satisfied", token)
+		} else if pv.size == 3 {
+			return []versionComparator{{">", lower}}, nil
+		}
+		return []versionComparator{{">=", pv.bump(pv.size - 1)}}, nil
+	case ">=":
+		return []versionComparator{{">=", lower}}, nil
+	case "<":
+		if pv.size == 0 {
+			return nil, fmt.Errorf("%s is never satisfied", token)
+		}
+		return []versionComparator{{"<", lower}}, nil
+	case "<=":
+		if pv.size == 0 {
+			return nil, nil
+		} else if pv.size == 3 {
+			return []versionComparator{{"<=", lower}}, nil
+		}
+		return []versionComparator{{"<", pv.bump(pv.size - 1)}}, nil
+	case "~":
+		if pv.size == 0 {
+			return nil, nil
+		} else if pv.size == 1 {
+			return []versionComparator{{">=", lower}, {"<", pv.bump(0)}}, nil
+		}
+		return []versionComparator{{">=", lower}, {"<", pv.bump(1)}}, nil
+	default:
+		if pv.size == 0 {
+			return nil, nil
+		}
+		return []versionComparator{{">=", lower}, {"<", pv.bump(pv.caretPosition())}}, nil
+	}
+}
+
+func parsePartialVersion(s string) (partialVersion, error) {
+	if s == "" {
+		return partialVersion{}, fmt.Errorf("missing version")
+	}
+
+	core := strings.TrimPrefix(s, "v")
+	var pv partialVersion
+
+	if strings.ContainsAny(core, "-+") {
+		v, err := ParseVersion(s)
+		if err != nil {
+			return partialVersion{}, err
+		}
+		return partialVersion{version: v, size: 3}, nil
+	}
+
+	parts := strings.Split(core, ".")
+	const coreSize = 3
+	if len(parts) > coreSize {
+		return partialVersion{}, fmt.Errorf("%q is not a version", s)
+	}
+
+	numbers := []*uint64{&pv.version.Major, &pv.version.Minor, &pv.version.Patch}
+	for i, part := range parts {
+		wildcard := part == "*" || part == "x" || part == "X"
+		if wildcard {
+			continue
+		} else if pv.size < i {
+			return partialVersion{}, fmt.Errorf("%q has numbers after a wildcard", s)
+		}
+
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || !isNumericIdentifier(part) {
+			return partialVersion{}, fmt.Errorf("%q is not a version", s)
+		}
+
+		*numbers[i] = n
+		pv.size++
+	}
+
+	return pv, nil
+}
+
+// bump returns the lowest version greater than every version sharing
+// the numbers of pv up to position (0 for major, 1 for minor, 2 for patch).
+func (pv partialVersion) bump(position int) Version {
+	v := pv.version
+
+	switch position {
+	case 0:
+		return Version{Major: v.Major + 1}
+	case 1:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+// caretPosition returns the position of the left-most non-zero number,
+// or of the last number given when they are all zero.
+func (pv partialVersion) caretPosition() int {
+	switch {
+	case pv.size == 1 || pv.version.Major > 0:
+		return 0
+	case pv.size == 2 || pv.version.Minor > 0:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstraintAllows(t *testing.T) {
+	type testCase struct {
+		constraint string
+		allowed    []string
+		denied     []string
+	}
+	testCases := []testCase{
+		{
+			constraint: "^2",
+			allowed:    []string{"2.0.0", "v2.4.1", "2.99.99"},
+			denied:     []string{"1.9.9", "3.0.0", "3.0.0-beta.1", "v3.1.0"},
+		},
+		{
+			constraint: "^2.4",
+			allowed:    []string{"2.4.0", "2.5.1"},
+			denied:     []string{"2.3.9", "3.0.0"},
+		},
+		{
+			constraint: "^0.2.3",
+			allowed:    []string{"0.2.3", "0.2.9"},
+			denied:     []string{"0.2.2", "0.3.0"},
+		},
+		{
+			constraint: "^0.0.3",
+			allowed:    []string{"0.0.3"},
+			denied:     []string{"0.0.4"},
+		},
+		{
+			constraint: "~2.4",
+			allowed:    []string{"2.4.0", "2.4.7"},
+			denied:     []string{"2.3.9", "2.5.0"},
+		},
+		{
+			constraint: "~2",
+			allowed:    []string{"2.0.0", "2.9.0"},
+			denied:     []string{"3.0.0"},
+		},
+		{
+			constraint: "~2.4.1",
+			allowed:    []string{"2.4.1", "2.4.9"},
+			denied:     []string{"2.4.0", "2.5.0"},
+		},
+		{
+			constraint: ">=2.3 <3",
+			allowed:    []string{"2.3.0", "2.9.9"},
+			denied:     []string{"2.2.9", "3.0.0", "3.0.0-rc.1"},
+		},
+		{
+			constraint: ">= 2.3, < 3",
+			allowed:    []string{"2.3.0"},
+			denied:     []string{"3.0.0"},
+		},
+		{
+			constraint: ">2.4.1 <=2.5",
+			allowed:    []string{"2.4.2", "2.5.9"},
+			denied:     []string{"2.4.1", "2.6.0"},
+		},
+		{
+			constraint: ">2.4",
+			allowed:    []string{"2.5.0"},
+			denied:     []string{"2.4.9"},
+		},
+		{
+			constraint: "<=2.4.1",
+			allowed:    []string{"2.4.1", "1.0.0"},
+			denied:     []string{"2.4.2"},
+		},
+		{
+			constraint: "2.4.x",
+			allowed:    []string{"2.4.0", "2.4.3"},
+			denied:     []string{"2.5.0"},
+		},
+		{
+			constraint: "=2.4.1",
+			allowed:    []string{"v2.4.1", "2.4.1+build.5"},
+			denied:     []string{"2.4.2"},
+		},
+		{
+			constraint: "^2 !=2.4.1",
+			allowed:    []string{"2.4.0"},
+			denied:     []string{"2.4.1"},
+		},
+		{
+			constraint: "^1 || ^3",
+			allowed:    []string{"1.2.0", "3.1.0"},
+			denied:     []string{"2.0.0"},
+		},
+		{
+			constraint: "*",
+			allowed:    []string{"0.0.1", "10.0.0"},
+			denied:     []string{"latest"},
+		},
+		{
+			constraint: ">=1.0.0-rc.1",
+			allowed:    []string{"1.0.0-rc.2", "1.0.0"},
+			denied:     []string{"1.0.0-beta.5"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tc.constraint)
+			assert.Nil(t, err, err)
+			assert.Equal(t, tc.constraint, c.String())
+
+			for _, version := range tc.allowed {
+				assert.True(t, c.Allows(version), version)
+			}
+
+			for _, version := range tc.denied {
+				assert.False(t, c.Allows(version), version)
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	testCases := map[string]string{
+		"":        `invalid constraint "": empty range`,
+		"^2 ||":   `invalid constraint "^2 ||": empty range`,
+		">=":      `invalid constraint ">=": operator >= without version`,
+		"^two":    `invalid constraint "^two": "two" is not a version`,
+		"2.4.1.0": `invalid constraint "2.4.1.0": "2.4.1.0" is not a version`,
+		"2.x.1":   `invalid constraint "2.x.1": "2.x.1" has numbers after a wildcard`,
+		"!=2.4":   `invalid constraint "!=2.4": !=2.4 requires a complete version`,
+		"<*":      `invalid constraint "<*": <* is never satisfied`,
+		"^1.0.0-": `invalid constraint "^1.0.0-": invalid semantic version "1.0.0-": ` +
+			`pre-release has an empty identifier`,
+	}
+
+	for input, expected := range testCases {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseConstraint(input)
+			assert.EqualError(t, err, expected)
+		})
+	}
+}
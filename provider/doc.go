@@ -19,15 +19,26 @@ Thus, for GitHub and GitLab systems, we will have github.go and gitlab.go files,
 		RestoreCacheRelease() (*Release, error)
 	}
 
+Providers that are able to list every release of a project also implement ReleasesFetcher,
+which is needed to look up a given version or to pick the last release according to the
+channel, the constraint or the version scheme set by the caller. Every provider of this
+package implements it; custom providers keep compiling without it.
+
+	type ReleasesFetcher interface {
+		FetchReleases(client HTTPClientPlugin) ([]*Release, error)
+	}
+
 # GitHub provider implementation
 
 	// GithubProvider is a provider for getting releases from Github.
-	type GitlabProvider struct {
+	type GithubProvider struct {
 		Host        string
 		Port        uint
 		Ssl         bool
 		ProjectPath string
 		Timeout     time.Duration
+		Scheme      VersionScheme
+		TagFilter   TagFilter
 	}
 
 	func (provider GithubProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
@@ -51,6 +62,8 @@ Thus, for GitHub and GitLab systems, we will have github.go and gitlab.go files,
 		Ssl         bool
 		ProjectPath string
 		Timeout     time.Duration
+		Scheme      VersionScheme
+		TagFilter   TagFilter
 	}
 
 	func (provider GitlabProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
@@ -64,5 +77,120 @@ Thus, for GitHub and GitLab systems, we will have github.go and gitlab.go files,
 	func (GitlabProvider) RestoreCacheRelease() (*Release, error) {
 		return deserializeRelease()
 	}
+
+# Go module proxy provider implementation
+
+GoProxyProvider queries a Go module proxy (GOPROXY) for the versions of a module.
+It honours comma and pipe separated fallbacks as well as "direct" and "off".
+Releases have no assets, so it may only be used to check for updates.
+
+	// GoProxyProvider is a provider for getting releases from a Go module proxy.
+	type GoProxyProvider struct {
+		ProxyURL   string
+		ModulePath string
+		Timeout    time.Duration
+	}
+
+# OCI registry provider implementation
+
+OCIProvider lists the tags of a repository through the Distribution API and takes
+the highest semantic version tag as the last release. The layers of its manifest
+become the release assets, named after the org.opencontainers.image.title annotation
+and carrying the layer digest, which is used to verify the downloaded file.
+Registries that answer with a Bearer challenge are handled by the token flow. As it
+implements AssetDownloader, the blobs are downloaded with the same credentials.
+
+	// OCIProvider is a provider for getting releases from an OCI registry.
+	type OCIProvider struct {
+		Host       string
+		Port       uint
+		Ssl        bool
+		Repository string
+		Username   string
+		Password   string
+		Timeout    time.Duration
+		Scheme     VersionScheme
+	}
+
+# Appcast provider implementation
+
+AppcastProvider reads a Sparkle appcast feed. Items sharing a version become one
+release whose assets are tagged with the platform of sparkle:os, so the same feed
+serves every client. As it implements AssetVerifier, the sparkle:edSignature of
+the downloaded asset is checked against PublicKey.
+
+	// AppcastProvider is a provider for getting releases from a Sparkle appcast feed.
+	type AppcastProvider struct {
+		URL       string
+		PublicKey string
+		Timeout   time.Duration
+		Scheme    VersionScheme
+	}
+
+# Multi provider implementation
+
+MultiProvider queries an ordered list of providers, e.g. GitHub and then an internal
+mirror, for regions where one of them is not reachable. With FirstSuccess, the first
+provider that answers wins; with HighestVersion, every provider is queried and the
+highest release is taken. RewriteURL, for which PrefixRewriter builds a prefix mapping,
+lets a release found on GitHub be downloaded from the mirror. Assets are verified and
+downloaded by the provider they were found on, e.g. an AppcastProvider checks the
+signatures of its own assets.
+
+	provider.MultiProvider{
+		Providers: []provider.UpdaterProvider{github, mirror},
+		Strategy:  provider.FirstSuccess,
+		RewriteURL: provider.PrefixRewriter(map[string]string{
+			"https://github.com/": "https://mirror.corp.com/github/",
+		}),
+	}
+
+# Versions
+
+Releases are compared by name following Semantic Versioning 2.0.0 precedence rules,
+so 1.0.0-rc.10 is greater than 1.0.0-rc.2 and build metadata is ignored. ParseVersion
+parses a version, with or without the leading "v", and reports why it is not valid.
+Names that are not semantic versions, e.g. nightly, are left out when the last release
+is picked, see ValidReleases.
+
+	v, err := provider.ParseVersion("v1.0.0-rc.1+build.5")
+	// v.Major = 1, v.Prerelease = ["rc", "1"], v.Build = ["build", "5"]
+
+	c, err := provider.CompareVersions("1.0.0-alpha", "1.0.0-alpha.1")
+	// c = -1
+
+ParseConstraint parses a range of semantic versions, e.g. ^2, ~2.4 or >=2.3 <3. Upper
+bounds never admit the pre-releases of their version, so ^2 rejects 3.0.0-beta.1.
+
+	c, err := provider.ParseConstraint(">=2.3 <3")
+	c.Allows("2.5.1") // true
+
+# Release metadata
+
+Critical, MinSupportedVersion and Rollout of a release are read from a marker hidden in its
+description, with key: value pairs separated by new lines, commas or semicolons.
+Release.Mandatory tells whether a program running a given version must update, and
+ReleaseNotes.Mandatory whether any release it skips over demands it. A rollout
+field makes the release staged, offered to a percentage of installations, as told by
+Release.Eligible.
+
+	<!-- caravela: critical: true, min_supported_version: 1.5.2, rollout: 25% -->
+
+# Tag filters
+
+In a monorepo, releases of different programs share the same list of tags, e.g. cli/v1.4.0
+and agent/v2.0.1. TagFilter, available on GitHub and GitLab providers, keeps only the
+releases of one program: a Prefix to strip or a Pattern whose first capture group is the
+version. Releases are then named after the version and compared as such, while Release.Tag
+keeps the original tag.
+
+	provider.GithubProvider{
+		Host:        "api.github.com",
+		Ssl:         true,
+		ProjectPath: "owner/monorepo",
+		TagFilter:   provider.TagFilter{Prefix: "cli/"},
+	}
+
+	provider.TagFilter{Pattern: regexp.MustCompile(`^cli-(\d+\.\d+\.\d+)$`)}
 */
 package provider
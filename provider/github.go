@@ -9,12 +9,18 @@ import (
 )
 
 // GithubProvider is a provider for getting releases from Github.
+//
+// Scheme tells how the releases are compared (SemVer by default). TagFilter,
+// when set, restricts the releases to those whose tags it selects, e.g. the
+// ones prefixed with cli/ in a monorepo.
 type GithubProvider struct {
 	Host        string
 	Port        uint
 	Ssl         bool
 	ProjectPath string
 	Timeout     time.Duration
+	Scheme      VersionScheme
+	TagFilter   TagFilter
 }
 
 // GithubRelease is a representation - in JSON form - of what Github
@@ -23,6 +29,8 @@ type GithubRelease struct {
 	Name        string    `json:"tag_name"`
 	Body        string    `json:"body"`
 	PublishedAt time.Time `json:"published_at"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
 	Assets      []struct {
 		Name string `json:"name"`
 		URL  string `json:"browser_download_url"`
@@ -30,6 +38,15 @@ type GithubRelease struct {
 }
 
 func (provider GithubProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
+	releases, err := provider.FetchReleases(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return LatestRelease(releases, provider.Scheme), nil
+}
+
+func (provider GithubProvider) FetchReleases(client HTTPClientPlugin) ([]*Release, error) {
 	initGithubProvider(&provider)
 	err := validateGithubProvider(provider)
 	if err != nil {
@@ -41,16 +58,11 @@ func (provider GithubProvider) FetchLastRelease(client HTTPClientPlugin) (*Relea
 		return nil, err
 	}
 
-	var lastRelease *Release
-	for _, release := range releases {
-		if lastRelease == nil {
-			lastRelease = release
-		} else if lastRelease.CompareTo(release) == -1 {
-			lastRelease = release
-		}
-	}
+	return filterReleases(releases, provider.TagFilter), nil
+}
 
-	return lastRelease, nil
+func (provider GithubProvider) VersionScheme() VersionScheme {
+	return schemeOrDefault(provider.Scheme)
 }
 
 func (GithubProvider) CacheRelease(r Release) error {
@@ -114,21 +126,19 @@ func convertGithubToBase(r *GithubRelease) *Release {
 		Name:        r.Name,
 		Description: r.Body,
 		ReleasedAt:  r.PublishedAt,
+		Prerelease:  r.Prerelease,
+		Draft:       r.Draft,
 	}
 
 	size := len(r.Assets)
-	t.Assets = make([]struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}, size)
+	t.Assets = make([]Asset, size)
 
 	for i, link := range r.Assets {
-		t.Assets[i] = struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{Name: link.Name, URL: link.URL}
+		t.Assets[i] = Asset{Name: link.Name, URL: link.URL}
 	}
 
+	applyReleaseMetadata(&t)
+
 	return &t
 }
 
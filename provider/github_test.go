@@ -60,15 +60,77 @@ func TestGithubFetchLastRelease(t *testing.T) {
 	assert.Equal(t, "v0.1.2", actual.Name)
 }
 
+func TestGithubFetchLastReleaseSkipsInvalidNames(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader(
+				[]byte(`[{"tag_name":"1.2.0"},{"tag_name":"nightly"},{"tag_name":"1.10.0"}]`))),
+		}, nil)
+
+	provider := GithubProvider{Host: "github.com", Port: 80, ProjectPath: "massis/oalienista"}
+	actual, err := provider.FetchLastRelease(m)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "1.10.0", actual.Name)
+}
+
+func TestGithubFetchLastReleaseScheme(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader(
+				[]byte(`[{"tag_name":"r99"},{"tag_name":"r123"},{"tag_name":"r100"}]`))),
+		}, nil)
+
+	provider := GithubProvider{Host: "github.com", Port: 80, ProjectPath: "massis/oalienista", Scheme: Monotonic{}}
+	actual, err := provider.FetchLastRelease(m)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "r123", actual.Name)
+	assert.Equal(t, Monotonic{}, provider.VersionScheme())
+}
+
+func TestGithubFetchLastReleaseTagFilter(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader(
+				[]byte(`[{"tag_name":"cli/v1.4.0"},{"tag_name":"agent/v2.0.1"},{"tag_name":"cli/v1.3.9"}]`))),
+		}, nil)
+
+	provider := GithubProvider{
+		Host: "github.com", Port: 80, ProjectPath: "massis/oalienista", TagFilter: TagFilter{Prefix: "cli/"},
+	}
+	actual, err := provider.FetchLastRelease(m)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v1.4.0", actual.Name)
+	assert.Equal(t, "cli/v1.4.0", actual.Tag)
+}
+
+func TestGithubProviderFetchReleases(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader(
+				[]byte(`[{"tag_name":"v0.1.0"},{"tag_name":"v0.1.1"}]`))),
+		}, nil)
+
+	provider := GithubProvider{Host: "github.com", Port: 80, ProjectPath: "massis/oalienista"}
+	actual, err := provider.FetchReleases(m)
+	assert.Nil(t, err, err)
+	assert.Len(t, actual, 2)
+	assert.Equal(t, SemVer{}, provider.VersionScheme())
+}
+
 func TestGithubCacheRelease(t *testing.T) {
 	release := &Release{
 		Name:        "v0.1.0-dev",
 		Description: "Development version.",
 		ReleasedAt:  time.Date(2023, 3, 6, 9, 59, 26, 0, time.UTC),
-		Assets: []struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{
+		Assets: []Asset{
 			{Name: "f1", URL: "u1"},
 			{Name: "f2", URL: "u2"},
 			{Name: "f3", URL: "u3"},
@@ -99,10 +161,7 @@ func TestGithubRestoreCacheRelease(t *testing.T) {
 		Name:        "v0.1.0-dev",
 		Description: "Development version.",
 		ReleasedAt:  time.Date(2023, 3, 6, 9, 59, 26, 0, time.UTC),
-		Assets: []struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{
+		Assets: []Asset{
 			{Name: "f1", URL: "u1"},
 			{Name: "f2", URL: "u2"},
 			{Name: "f3", URL: "u3"},
@@ -219,7 +278,9 @@ func TestFetchGithubReleases(t *testing.T) {
 }
 
 func TestBuildGithubServiceUrl(t *testing.T) {
-	p := GithubProvider{"www.domain.com.br", 80, false, "aureliano/caravela", time.Second * 30}
+	p := GithubProvider{
+		Host: "www.domain.com.br", Port: 80, Ssl: false, ProjectPath: "aureliano/caravela", Timeout: time.Second * 30,
+	}
 	expected := "http://www.domain.com.br:80/repos/aureliano/caravela/releases"
 	actual := buildGithubServiceURL(p)
 
@@ -227,7 +288,9 @@ func TestBuildGithubServiceUrl(t *testing.T) {
 }
 
 func TestBuildGithubServiceUrlSsl(t *testing.T) {
-	p := GithubProvider{"www.domain.com.br", 80, true, "aureliano/caravela", time.Second * 30}
+	p := GithubProvider{
+		Host: "www.domain.com.br", Port: 80, Ssl: true, ProjectPath: "aureliano/caravela", Timeout: time.Second * 30,
+	}
 	expected := "https://www.domain.com.br:80/repos/aureliano/caravela/releases"
 	actual := buildGithubServiceURL(p)
 
@@ -313,6 +376,22 @@ func TestConvertGithubToBase(t *testing.T) {
 	}
 }
 
+func TestConvertGithubToBaseFlags(t *testing.T) {
+	r := convertGithubToBase(&GithubRelease{Name: "v0.2.0-rc.1", Prerelease: true})
+	assert.True(t, r.Prerelease)
+	assert.False(t, r.Draft)
+
+	r = convertGithubToBase(&GithubRelease{Name: "v0.2.0", Draft: true})
+	assert.False(t, r.Prerelease)
+	assert.True(t, r.Draft)
+}
+
+func TestConvertGithubToBaseMetadata(t *testing.T) {
+	r := convertGithubToBase(&GithubRelease{Name: "v1.6.0", Body: "<!-- caravela: min_supported_version: 1.5.2 -->"})
+	assert.False(t, r.Critical)
+	assert.Equal(t, "1.5.2", r.MinSupportedVersion)
+}
+
 func TestValidateGithubProviderInvalidHost(t *testing.T) {
 	p := GithubProvider{Host: "", Port: 80, ProjectPath: "massis/oalienista"}
 	expected := "host is required"
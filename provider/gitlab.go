@@ -10,12 +10,18 @@ import (
 )
 
 // GitlabProvider is a provider for getting releases from Gitlab.
+//
+// Scheme tells how the releases are compared (SemVer by default). TagFilter,
+// when set, restricts the releases to those whose tags it selects, e.g. the
+// ones prefixed with cli/ in a monorepo.
 type GitlabProvider struct {
 	Host        string
 	Port        uint
 	Ssl         bool
 	ProjectPath string
 	Timeout     time.Duration
+	Scheme      VersionScheme
+	TagFilter   TagFilter
 }
 
 // GitlabRelease is a representation - in JSON form - of what Gitlab
@@ -33,6 +39,15 @@ type GitlabRelease struct {
 }
 
 func (provider GitlabProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
+	releases, err := provider.FetchReleases(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return LatestRelease(releases, provider.Scheme), nil
+}
+
+func (provider GitlabProvider) FetchReleases(client HTTPClientPlugin) ([]*Release, error) {
 	initGitlabProvider(&provider)
 	err := validateGitlabProvider(provider)
 	if err != nil {
@@ -44,16 +59,11 @@ func (provider GitlabProvider) FetchLastRelease(client HTTPClientPlugin) (*Relea
 		return nil, err
 	}
 
-	var lastRelease *Release
-	for _, release := range releases {
-		if lastRelease == nil {
-			lastRelease = release
-		} else if lastRelease.CompareTo(release) == -1 {
-			lastRelease = release
-		}
-	}
+	return filterReleases(releases, provider.TagFilter), nil
+}
 
-	return lastRelease, nil
+func (provider GitlabProvider) VersionScheme() VersionScheme {
+	return schemeOrDefault(provider.Scheme)
 }
 
 func (GitlabProvider) CacheRelease(r Release) error {
@@ -121,18 +131,14 @@ func convertGitlabToBase(r *GitlabRelease) *Release {
 	}
 
 	size := len(r.Assets.Links)
-	t.Assets = make([]struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}, size)
+	t.Assets = make([]Asset, size)
 
 	for i, link := range r.Assets.Links {
-		t.Assets[i] = struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{Name: link.Name, URL: link.URL}
+		t.Assets[i] = Asset{Name: link.Name, URL: link.URL}
 	}
 
+	applyReleaseMetadata(&t)
+
 	return &t
 }
 
@@ -60,15 +60,62 @@ func TestGitlabFetchLastRelease(t *testing.T) {
 	assert.Equal(t, "v0.1.2", actual.Name)
 }
 
+func TestGitlabFetchLastReleaseScheme(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader(
+				[]byte(`[{"tag_name":"r99"},{"tag_name":"r123"},{"tag_name":"r100"}]`))),
+		}, nil)
+
+	provider := GitlabProvider{Host: "gitlab.com", Port: 80, ProjectPath: "massis/oalienista", Scheme: Monotonic{}}
+	actual, err := provider.FetchLastRelease(m)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "r123", actual.Name)
+	assert.Equal(t, Monotonic{}, provider.VersionScheme())
+}
+
+func TestGitlabFetchLastReleaseTagFilter(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader(
+				[]byte(`[{"tag_name":"cli/v1.4.0"},{"tag_name":"agent/v2.0.1"},{"tag_name":"cli/v1.3.9"}]`))),
+		}, nil)
+
+	provider := GitlabProvider{
+		Host: "gitlab.com", Port: 80, ProjectPath: "massis/oalienista", TagFilter: TagFilter{Prefix: "cli/"},
+	}
+	actual, err := provider.FetchLastRelease(m)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v1.4.0", actual.Name)
+	assert.Equal(t, "cli/v1.4.0", actual.Tag)
+}
+
+func TestGitlabProviderFetchReleases(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader(
+				[]byte(`[{"tag_name":"v0.1.0"},{"tag_name":"v0.1.1"}]`))),
+		}, nil)
+
+	provider := GitlabProvider{Host: "gitlab.com", Port: 80, ProjectPath: "massis/oalienista"}
+	actual, err := provider.FetchReleases(m)
+	assert.Nil(t, err, err)
+	assert.Len(t, actual, 2)
+	assert.Equal(t, SemVer{}, provider.VersionScheme())
+}
+
 func TestGitlabCacheRelease(t *testing.T) {
 	release := &Release{
 		Name:        "v0.1.0-dev",
 		Description: "Development version.",
 		ReleasedAt:  time.Date(2023, 3, 6, 9, 59, 26, 0, time.UTC),
-		Assets: []struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{
+		Assets: []Asset{
 			{Name: "f1", URL: "u1"},
 			{Name: "f2", URL: "u2"},
 			{Name: "f3", URL: "u3"},
@@ -99,10 +146,7 @@ func TestGitlabRestoreCacheRelease(t *testing.T) {
 		Name:        "v0.1.0-dev",
 		Description: "Development version.",
 		ReleasedAt:  time.Date(2023, 3, 6, 9, 59, 26, 0, time.UTC),
-		Assets: []struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{
+		Assets: []Asset{
 			{Name: "f1", URL: "u1"},
 			{Name: "f2", URL: "u2"},
 			{Name: "f3", URL: "u3"},
@@ -219,7 +263,9 @@ func TestGitlabFetchReleases(t *testing.T) {
 }
 
 func TestBuildGitlabServiceUrl(t *testing.T) {
-	p := GitlabProvider{"www.domain.com.br", 80, false, "aureliano/caravela", time.Second * 30}
+	p := GitlabProvider{
+		Host: "www.domain.com.br", Port: 80, Ssl: false, ProjectPath: "aureliano/caravela", Timeout: time.Second * 30,
+	}
 	expected := "http://www.domain.com.br:80/api/v4/projects/aureliano%2Fcaravela/releases"
 	actual := buildGitlabServiceURL(p)
 
@@ -227,7 +273,9 @@ func TestBuildGitlabServiceUrl(t *testing.T) {
 }
 
 func TestBuildGitlabServiceUrlSsl(t *testing.T) {
-	p := GitlabProvider{"www.domain.com.br", 80, true, "aureliano/caravela", time.Second * 30}
+	p := GitlabProvider{
+		Host: "www.domain.com.br", Port: 80, Ssl: true, ProjectPath: "aureliano/caravela", Timeout: time.Second * 30,
+	}
 	expected := "https://www.domain.com.br:80/api/v4/projects/aureliano%2Fcaravela/releases"
 	actual := buildGitlabServiceURL(p)
 
@@ -0,0 +1,327 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+// GoProxyProvider is a provider for getting releases from a Go module proxy.
+//
+// ProxyURL follows the GOPROXY syntax: a list of proxy URLs separated by comma or pipe
+// and the special values "direct" and "off". When empty, the GOPROXY environment
+// variable is used, falling back to the go command default. Releases found by this
+// provider have no assets, so it is only meant for checking updates.
+type GoProxyProvider struct {
+	ProxyURL   string
+	ModulePath string
+	Timeout    time.Duration
+}
+
+// GoProxyInfo is a representation - in JSON form - of what a Go module
+// proxy returns when the .info or @latest endpoints are called.
+type GoProxyInfo struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+type goProxy struct {
+	url string
+	// fallbackOnError tells whether the next proxy must be tried on any error
+	// or only when the module is not found (404 or 410).
+	fallbackOnError bool
+}
+
+type goProxyError struct {
+	statusCode int
+}
+
+func (e *goProxyError) Error() string {
+	return fmt.Sprintf("go proxy integration error: %d", e.statusCode)
+}
+
+func (provider GoProxyProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
+	var release *Release
+	err := queryGoProxies(provider, func(p GoProxyProvider, proxyURL string) (err error) {
+		release, err = fetchGoProxyLastRelease(p, proxyURL, client)
+		return err
+	})
+
+	return release, err
+}
+
+// FetchReleases lists the versions known by the proxy, along with their release
+// date taken from their .info files.
+func (provider GoProxyProvider) FetchReleases(client HTTPClientPlugin) ([]*Release, error) {
+	var releases []*Release
+	err := queryGoProxies(provider, func(p GoProxyProvider, proxyURL string) error {
+		versions, err := fetchGoProxyVersions(p, proxyURL, client)
+		if err != nil {
+			return err
+		} else if len(versions) > 0 {
+			releases = make([]*Release, len(versions))
+			for i, version := range versions {
+				releases[i], err = fetchGoProxyRelease(p, proxyURL, version, client)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		release, err := fetchGoProxyLastRelease(p, proxyURL, client)
+		if err == nil {
+			releases = []*Release{release}
+		}
+		return err
+	})
+
+	return releases, err
+}
+
+func (GoProxyProvider) CacheRelease(r Release) error {
+	return serializeRelease(&r)
+}
+
+func (GoProxyProvider) RestoreCacheRelease() (*Release, error) {
+	return deserializeRelease()
+}
+
+// queryGoProxies calls fetch with the proxies of the GOPROXY list in turn, until
+// one of them answers or the list says not to go further.
+func queryGoProxies(p GoProxyProvider, fetch func(p GoProxyProvider, proxyURL string) error) error {
+	initGoProxyProvider(&p)
+	err := validateGoProxyProvider(p)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, proxy := range parseGoProxyList(p.ProxyURL) {
+		switch proxy.url {
+		case "off":
+			return fmt.Errorf("module lookup disabled by GOPROXY=off")
+		case "direct":
+			return fmt.Errorf("direct module lookup of %s is not supported", p.ModulePath)
+		}
+
+		lastErr = fetch(p, proxy.url)
+		if lastErr == nil {
+			return nil
+		}
+
+		if !proxy.fallbackOnError && !isGoProxyNotFound(lastErr) {
+			return lastErr
+		}
+	}
+
+	return lastErr
+}
+
+func fetchGoProxyLastRelease(p GoProxyProvider, proxyURL string, client HTTPClientPlugin) (*Release, error) {
+	versions, err := fetchGoProxyVersions(p, proxyURL, client)
+	if err != nil {
+		return nil, err
+	}
+
+	versions = validGoProxyVersions(versions)
+
+	if len(versions) == 0 {
+		info, err := fetchGoProxyInfo(p, buildGoProxyServiceURL(proxyURL, p.ModulePath, "@latest"), client)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertGoProxyToBase(info), nil
+	}
+
+	lastVersion := versions[0]
+	for _, version := range versions[1:] {
+		if compareVersions(lastVersion, version) == -1 {
+			lastVersion = version
+		}
+	}
+
+	return fetchGoProxyRelease(p, proxyURL, lastVersion, client)
+}
+
+// fetchGoProxyRelease fetches the .info file of version.
+func fetchGoProxyRelease(p GoProxyProvider, proxyURL, version string, client HTTPClientPlugin) (*Release, error) {
+	endpoint := fmt.Sprintf("@v/%s.info", escapeGoModulePath(version))
+	info, err := fetchGoProxyInfo(p, buildGoProxyServiceURL(proxyURL, p.ModulePath, endpoint), client)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertGoProxyToBase(info), nil
+}
+
+// validGoProxyVersions leaves out the versions that are not semantic versions,
+// so they are never compared lexically.
+func validGoProxyVersions(versions []string) []string {
+	valid := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if (SemVer{}).Validate(version) == nil {
+			valid = append(valid, version)
+		}
+	}
+
+	return valid
+}
+
+func fetchGoProxyVersions(p GoProxyProvider, proxyURL string, client HTTPClientPlugin) ([]string, error) {
+	srvURL := buildGoProxyServiceURL(proxyURL, p.ModulePath, "@v/list")
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srvURL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &goProxyError{statusCode: resp.StatusCode}
+	}
+
+	var versions []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		version := strings.TrimSpace(scanner.Text())
+		if version != "" {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions, scanner.Err()
+}
+
+func fetchGoProxyInfo(p GoProxyProvider, srvURL string, client HTTPClientPlugin) (*GoProxyInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srvURL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &goProxyError{statusCode: resp.StatusCode}
+	}
+
+	info := &GoProxyInfo{}
+	err = json.NewDecoder(resp.Body).Decode(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func buildGoProxyServiceURL(proxyURL, modulePath, endpoint string) string {
+	baseURL := strings.TrimSuffix(proxyURL, "/")
+	return fmt.Sprintf("%s/%s/%s", baseURL, escapeGoModulePath(modulePath), endpoint)
+}
+
+func convertGoProxyToBase(info *GoProxyInfo) *Release {
+	return &Release{
+		Name:       info.Version,
+		ReleasedAt: info.Time,
+	}
+}
+
+// parseGoProxyList splits a GOPROXY value the same way the go command does.
+// A proxy followed by a comma is only skipped when the module is not found,
+// whereas one followed by a pipe is skipped on any error.
+func parseGoProxyList(list string) []goProxy {
+	var proxies []goProxy
+
+	for list != "" {
+		i := strings.IndexAny(list, ",|")
+		var entry string
+		fallbackOnError := false
+
+		if i < 0 {
+			entry, list = list, ""
+		} else {
+			entry = list[:i]
+			fallbackOnError = list[i] == '|'
+			list = list[i+1:]
+		}
+
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			proxies = append(proxies, goProxy{url: entry, fallbackOnError: fallbackOnError})
+		}
+	}
+
+	return proxies
+}
+
+// escapeGoModulePath applies the case encoding used by module proxies, where
+// every upper-case letter is replaced by an exclamation mark followed by the
+// letter in lower case.
+func escapeGoModulePath(path string) string {
+	var builder strings.Builder
+
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			builder.WriteByte('!')
+			builder.WriteRune(unicode.ToLower(r))
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+func isGoProxyNotFound(err error) bool {
+	var proxyErr *goProxyError
+	if !errors.As(err, &proxyErr) {
+		return false
+	}
+
+	return proxyErr.statusCode == http.StatusNotFound || proxyErr.statusCode == http.StatusGone
+}
+
+func validateGoProxyProvider(p GoProxyProvider) error {
+	switch {
+	case p.ModulePath == "":
+		return fmt.Errorf("module path is required")
+	case len(parseGoProxyList(p.ProxyURL)) == 0:
+		return fmt.Errorf("proxy url is required")
+	default:
+		return nil
+	}
+}
+
+func initGoProxyProvider(p *GoProxyProvider) {
+	const timeout = time.Second * 30
+
+	if p.ProxyURL == "" {
+		p.ProxyURL = os.Getenv("GOPROXY")
+	}
+
+	if p.ProxyURL == "" {
+		p.ProxyURL = defaultGoProxy
+	}
+
+	if p.Timeout == 0 {
+		p.Timeout = timeout
+	}
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func goProxyRequestTo(url string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == url
+	})
+}
+
+func goProxyResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func TestGoProxyFetchLastReleaseValidationError(t *testing.T) {
+	m := new(mockDecorator)
+	p := GoProxyProvider{}
+
+	_, err := p.FetchLastRelease(m)
+	assert.Equal(t, "module path is required", err.Error())
+}
+
+func TestGoProxyFetchLastReleaseOff(t *testing.T) {
+	m := new(mockDecorator)
+	p := GoProxyProvider{ProxyURL: "off", ModulePath: "github.com/aureliano/caravela"}
+
+	_, err := p.FetchLastRelease(m)
+	assert.Equal(t, "module lookup disabled by GOPROXY=off", err.Error())
+	m.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGoProxyFetchLastReleaseDirect(t *testing.T) {
+	m := new(mockDecorator)
+	p := GoProxyProvider{ProxyURL: "direct", ModulePath: "github.com/aureliano/caravela"}
+
+	_, err := p.FetchLastRelease(m)
+	assert.Equal(t, "direct module lookup of github.com/aureliano/caravela is not supported", err.Error())
+}
+
+func TestGoProxyFetchLastRelease(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, "v0.1.0\nv0.1.10\nv0.1.2\n"), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/v0.1.10.info")).Return(
+		goProxyResponse(http.StatusOK, `{"Version":"v0.1.10","Time":"2023-03-09T14:11:18Z"}`), nil)
+
+	p := GoProxyProvider{ProxyURL: "https://proxy.golang.org", ModulePath: "github.com/aureliano/caravela"}
+	actual, err := p.FetchLastRelease(m)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v0.1.10", actual.Name)
+	assert.Equal(t, time.Date(2023, 3, 9, 14, 11, 18, 0, time.UTC), actual.ReleasedAt)
+	assert.Empty(t, actual.Assets)
+}
+
+func TestGoProxyFetchLastReleaseLatest(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, ""), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@latest")).Return(
+		goProxyResponse(http.StatusOK, `{"Version":"v0.0.0-20230309141118-abcdefabcdef",`+
+			`"Time":"2023-03-09T14:11:18Z"}`), nil)
+
+	p := GoProxyProvider{ProxyURL: "https://proxy.golang.org/", ModulePath: "github.com/aureliano/caravela"}
+	actual, err := p.FetchLastRelease(m)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v0.0.0-20230309141118-abcdefabcdef", actual.Name)
+}
+
+func TestGoProxyFetchLastReleaseFallbackOnNotFound(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.corp.com/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusGone, ""), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, "v0.1.0\n"), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/v0.1.0.info")).Return(
+		goProxyResponse(http.StatusOK, `{"Version":"v0.1.0","Time":"2023-03-09T14:11:18Z"}`), nil)
+
+	p := GoProxyProvider{
+		ProxyURL:   "https://proxy.corp.com,https://proxy.golang.org,direct",
+		ModulePath: "github.com/aureliano/caravela",
+	}
+	actual, err := p.FetchLastRelease(m)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v0.1.0", actual.Name)
+}
+
+func TestGoProxyFetchLastReleaseNoFallbackOnError(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.corp.com/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusInternalServerError, ""), nil)
+
+	p := GoProxyProvider{
+		ProxyURL:   "https://proxy.corp.com,https://proxy.golang.org",
+		ModulePath: "github.com/aureliano/caravela",
+	}
+	_, err := p.FetchLastRelease(m)
+
+	assert.Equal(t, "go proxy integration error: 500", err.Error())
+	m.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestGoProxyFetchLastReleaseFallbackOnAnyError(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.corp.com/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusBadGateway, ""), errors.New("connection refused"))
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, "v0.1.0\n"), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/v0.1.0.info")).Return(
+		goProxyResponse(http.StatusOK, `{"Version":"v0.1.0","Time":"2023-03-09T14:11:18Z"}`), nil)
+
+	p := GoProxyProvider{
+		ProxyURL:   "https://proxy.corp.com|https://proxy.golang.org",
+		ModulePath: "github.com/aureliano/caravela",
+	}
+	actual, err := p.FetchLastRelease(m)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v0.1.0", actual.Name)
+}
+
+func TestGoProxyFetchLastReleaseBrokenJson(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, "v0.1.0\n"), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/v0.1.0.info")).Return(
+		goProxyResponse(http.StatusOK, `{]`), nil)
+
+	p := GoProxyProvider{ProxyURL: "https://proxy.golang.org", ModulePath: "github.com/aureliano/caravela"}
+	_, err := p.FetchLastRelease(m)
+
+	assert.NotNil(t, err)
+}
+
+func TestGoProxyFetchReleases(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, "v0.1.0\nv0.1.10\nv0.1.2\n"), nil)
+	for i, version := range []string{"v0.1.0", "v0.1.10", "v0.1.2"} {
+		info := fmt.Sprintf(`{"Version":"%s","Time":"2023-03-0%dT14:11:18Z"}`, version, i+1)
+		m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/"+version+".info")).Return(
+			goProxyResponse(http.StatusOK, info), nil)
+	}
+
+	p := GoProxyProvider{ProxyURL: "https://proxy.golang.org", ModulePath: "github.com/aureliano/caravela"}
+	actual, err := p.FetchReleases(m)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, []*Release{
+		{Name: "v0.1.0", ReleasedAt: time.Date(2023, 3, 1, 14, 11, 18, 0, time.UTC)},
+		{Name: "v0.1.10", ReleasedAt: time.Date(2023, 3, 2, 14, 11, 18, 0, time.UTC)},
+		{Name: "v0.1.2", ReleasedAt: time.Date(2023, 3, 3, 14, 11, 18, 0, time.UTC)},
+	}, actual)
+}
+
+func TestGoProxyFetchReleasesInfoError(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, "v0.1.0\n"), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/v0.1.0.info")).Return(
+		goProxyResponse(http.StatusInternalServerError, ""), nil)
+
+	p := GoProxyProvider{ProxyURL: "https://proxy.golang.org", ModulePath: "github.com/aureliano/caravela"}
+	_, err := p.FetchReleases(m)
+
+	assert.Equal(t, "go proxy integration error: 500", err.Error())
+}
+
+func TestGoProxyFetchReleasesLatest(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@v/list")).Return(
+		goProxyResponse(http.StatusOK, ""), nil)
+	m.On("Do", goProxyRequestTo("https://proxy.golang.org/github.com/aureliano/caravela/@latest")).Return(
+		goProxyResponse(http.StatusOK, `{"Version":"v0.1.0","Time":"2023-03-09T14:11:18Z"}`), nil)
+
+	p := GoProxyProvider{ProxyURL: "https://proxy.golang.org", ModulePath: "github.com/aureliano/caravela"}
+	actual, err := p.FetchReleases(m)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, []*Release{{Name: "v0.1.0", ReleasedAt: time.Date(2023, 3, 9, 14, 11, 18, 0, time.UTC)}}, actual)
+}
+
+func TestGoProxyFetchReleasesOff(t *testing.T) {
+	p := GoProxyProvider{ProxyURL: "off", ModulePath: "github.com/aureliano/caravela"}
+	_, err := p.FetchReleases(new(mockDecorator))
+
+	assert.Equal(t, "module lookup disabled by GOPROXY=off", err.Error())
+}
+
+func TestParseGoProxyList(t *testing.T) {
+	actual := parseGoProxyList("https://proxy.corp.com|https://proxy.golang.org, direct")
+	expected := []goProxy{
+		{url: "https://proxy.corp.com", fallbackOnError: true},
+		{url: "https://proxy.golang.org", fallbackOnError: false},
+		{url: "direct", fallbackOnError: false},
+	}
+
+	assert.Equal(t, expected, actual)
+	assert.Empty(t, parseGoProxyList(" , "))
+}
+
+func TestEscapeGoModulePath(t *testing.T) {
+	assert.Equal(t, "github.com/!azure/azure-sdk-for-go", escapeGoModulePath("github.com/Azure/azure-sdk-for-go"))
+	assert.Equal(t, "github.com/aureliano/caravela", escapeGoModulePath("github.com/aureliano/caravela"))
+}
+
+func TestInitGoProxyProvider(t *testing.T) {
+	t.Setenv("GOPROXY", "")
+	p := GoProxyProvider{}
+	initGoProxyProvider(&p)
+
+	assert.Equal(t, "https://proxy.golang.org,direct", p.ProxyURL)
+	assert.Equal(t, time.Second*30, p.Timeout)
+
+	t.Setenv("GOPROXY", "https://proxy.corp.com")
+	p = GoProxyProvider{Timeout: time.Second * 5}
+	initGoProxyProvider(&p)
+
+	assert.Equal(t, "https://proxy.corp.com", p.ProxyURL)
+	assert.Equal(t, time.Second*5, p.Timeout)
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	ociCriticalAnnotation            = "io.caravela.critical"
+	ociMinSupportedVersionAnnotation = "io.caravela.min-supported-version"
+	ociRolloutAnnotation             = "io.caravela.rollout"
+)
+
+// releaseMetadataRegex matches the marker holding the metadata of a release
+// within its description, e.g. <!-- caravela: critical: true -->. Being an
+// HTML comment, it is hidden when the description is rendered.
+var releaseMetadataRegex = regexp.MustCompile(`(?s)<!--\s*caravela:?(.*?)-->`)
+
+// Mandatory tells whether updating to the release is mandatory for a program
+// running currver, that is, whether the release is critical or currver is older
+// than its minimum supported version. Versions are compared according to scheme.
+// SemVer is used when scheme is nil.
+//
+// Only the flags of this release are considered, so a critical release skipped
+// over by a jump of several versions goes unnoticed. See ReleaseNotes.Mandatory
+// to consider every release in between.
+func (r1 *Release) Mandatory(currver string, scheme VersionScheme) bool {
+	if r1.Critical {
+		return true
+	}
+
+	if r1.MinSupportedVersion == "" {
+		return false
+	}
+
+	return schemeOrDefault(scheme).Compare(currver, r1.MinSupportedVersion) == -1
+}
+
+// applyReleaseMetadata reads the critical, min_supported_version and rollout fields
+// from the marker of the release description, if any. Fields are key: value pairs
+// separated by new lines, commas or semicolons.
+func applyReleaseMetadata(release *Release) {
+	for _, match := range releaseMetadataRegex.FindAllStringSubmatch(release.Description, -1) {
+		fields := strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == '\n' || r == '\r' || r == ',' || r == ';'
+		})
+
+		for _, field := range fields {
+			separator := strings.IndexAny(field, ":=")
+			if separator == -1 {
+				continue
+			}
+
+			key := strings.ToLower(strings.TrimSpace(field[:separator]))
+			value := strings.TrimSpace(field[separator+1:])
+			setReleaseMetadata(release, key, value)
+		}
+	}
+}
+
+func setReleaseMetadata(release *Release, key, value string) {
+	switch strings.ReplaceAll(key, "-", "_") {
+	case "critical":
+		if critical, err := strconv.ParseBool(value); err == nil {
+			release.Critical = critical
+		}
+	case "min_supported_version":
+		release.MinSupportedVersion = value
+	case "rollout":
+		if rollout, ok := parseRollout(value); ok {
+			release.Rollout = rollout
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyReleaseMetadata(t *testing.T) {
+	release := &Release{Description: "Fixes CVE-2023-1234.\n\n<!-- caravela\ncritical: true\n" +
+		"min_supported_version: 1.5.2\n-->"}
+	applyReleaseMetadata(release)
+	assert.True(t, release.Critical)
+	assert.Equal(t, "1.5.2", release.MinSupportedVersion)
+
+	release = &Release{Description: "<!-- caravela: critical=false, min-supported-version = v1.5.2 -->"}
+	applyReleaseMetadata(release)
+	assert.False(t, release.Critical)
+	assert.Equal(t, "v1.5.2", release.MinSupportedVersion)
+
+	release = &Release{Description: "<!-- caravela: rollout: 25% -->"}
+	applyReleaseMetadata(release)
+	assert.Equal(t, 25, release.Rollout)
+}
+
+func TestApplyReleaseMetadataIgnored(t *testing.T) {
+	descriptions := []string{
+		"",
+		"critical: true",
+		"<!-- critical: true -->",
+		"<!-- caravela: critical: yes; unknown: 1; no separator -->",
+		"<!-- caravela: rollout: half -->",
+	}
+
+	for _, description := range descriptions {
+		release := &Release{Description: description}
+		applyReleaseMetadata(release)
+		assert.Equal(t, &Release{Description: description}, release, description)
+	}
+}
+
+func TestReleaseMandatory(t *testing.T) {
+	assert.False(t, (&Release{Name: "1.6.0"}).Mandatory("1.2.0", nil))
+	assert.True(t, (&Release{Name: "1.6.0", Critical: true}).Mandatory("1.5.2", nil))
+
+	release := &Release{Name: "1.6.0", MinSupportedVersion: "1.5.2"}
+	assert.True(t, release.Mandatory("1.2.0", nil))
+	assert.True(t, release.Mandatory("v1.5.2-rc.1", nil))
+	assert.False(t, release.Mandatory("1.5.2", nil))
+	assert.False(t, release.Mandatory("1.5.10", nil))
+
+	release = &Release{Name: "2024.10", MinSupportedVersion: "2024.02"}
+	assert.True(t, release.Mandatory("2023.12", CalVer{}))
+	assert.False(t, release.Mandatory("2024.2.1", CalVer{}))
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strategy tells how MultiProvider picks the last release among its providers.
+type Strategy int
+
+const (
+	// FirstSuccess takes the release of the first provider that answers without error.
+	FirstSuccess Strategy = iota
+	// HighestVersion queries every provider and takes the highest release found.
+	HighestVersion
+)
+
+// MultiProvider is a provider that queries an ordered list of providers,
+// e.g. GitHub and then an internal mirror.
+//
+// RewriteURL, when set, is applied to the asset URLs of the release found,
+// so a release found on one provider can be downloaded from another host.
+// Scheme is used to compare the releases found with HighestVersion.
+//
+// Assets record the provider they were found on, so they are verified and
+// downloaded by that provider when it implements AssetVerifier or AssetDownloader.
+type MultiProvider struct {
+	Providers  []UpdaterProvider
+	Strategy   Strategy
+	RewriteURL func(url string) string
+	Scheme     VersionScheme
+}
+
+func (provider MultiProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
+	err := validateMultiProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastRelease *Release
+	var errs []string
+
+	for i, p := range provider.Providers {
+		release, e := p.FetchLastRelease(client)
+		if e != nil {
+			errs = append(errs, fmt.Sprintf("provider %d: %s", i, e))
+			continue
+		} else if release == nil || !followsScheme(release, provider.Scheme) {
+			continue
+		}
+
+		if lastRelease == nil || lastRelease.CompareWith(release, provider.Scheme) == -1 {
+			lastRelease = fromProvider(release, i)
+		}
+
+		if provider.Strategy == FirstSuccess {
+			break
+		}
+	}
+
+	if lastRelease == nil && len(errs) > 0 {
+		return nil, fmt.Errorf("all providers failed: %s", strings.Join(errs, "; "))
+	}
+
+	return rewriteAssetURLs(lastRelease, provider.RewriteURL), nil
+}
+
+// FetchReleases lists the releases of the providers. With FirstSuccess, the list
+// of the first provider that answers is taken; with HighestVersion, the lists of
+// every provider are merged, the first provider winning when a release is found
+// on more than one. Providers that are not able to list their releases only
+// contribute with their last release.
+func (provider MultiProvider) FetchReleases(client HTTPClientPlugin) ([]*Release, error) {
+	err := validateMultiProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []*Release
+	var errs []string
+	found := make(map[string]bool)
+	answered := false
+
+	for i, p := range provider.Providers {
+		list, e := fetchProviderReleases(p, client)
+		if e != nil {
+			errs = append(errs, fmt.Sprintf("provider %d: %s", i, e))
+			continue
+		}
+
+		answered = true
+		for _, release := range list {
+			if !found[release.Name] {
+				found[release.Name] = true
+				releases = append(releases, rewriteAssetURLs(fromProvider(release, i), provider.RewriteURL))
+			}
+		}
+
+		if provider.Strategy == FirstSuccess && len(list) > 0 {
+			break
+		}
+	}
+
+	if !answered && len(errs) > 0 {
+		return nil, fmt.Errorf("all providers failed: %s", strings.Join(errs, "; "))
+	}
+
+	return releases, nil
+}
+
+func (provider MultiProvider) VersionScheme() VersionScheme {
+	return schemeOrDefault(provider.Scheme)
+}
+
+// VerifyAsset verifies asset with the provider it was found on.
+func (provider MultiProvider) VerifyAsset(asset Asset, path string) error {
+	p, err := assetProvider(provider, asset)
+	if err != nil {
+		return err
+	}
+
+	verifier, ok := p.(AssetVerifier)
+	if !ok {
+		return fmt.Errorf("provider %d is not able to verify %s", asset.Provider, asset.Name)
+	}
+
+	return verifier.VerifyAsset(asset, path)
+}
+
+// DownloadClient decorates client as the provider release was found on does, if
+// it is an AssetDownloader.
+func (provider MultiProvider) DownloadClient(client HTTPClientPlugin, release *Release) HTTPClientPlugin {
+	if release == nil || len(release.Assets) == 0 {
+		return client
+	}
+
+	p, err := assetProvider(provider, release.Assets[0])
+	if err != nil {
+		return client
+	}
+
+	if downloader, ok := p.(AssetDownloader); ok {
+		return downloader.DownloadClient(client, release)
+	}
+
+	return client
+}
+
+func (MultiProvider) CacheRelease(r Release) error {
+	return serializeRelease(&r)
+}
+
+func (MultiProvider) RestoreCacheRelease() (*Release, error) {
+	return deserializeRelease()
+}
+
+func fetchProviderReleases(p UpdaterProvider, client HTTPClientPlugin) ([]*Release, error) {
+	if fetcher, ok := p.(ReleasesFetcher); ok {
+		return fetcher.FetchReleases(client)
+	}
+
+	release, err := p.FetchLastRelease(client)
+	if err != nil || release == nil {
+		return nil, err
+	}
+
+	return []*Release{release}, nil
+}
+
+// fromProvider returns a copy of release whose assets record the index of the
+// provider they were found on.
+func fromProvider(release *Release, index int) *Release {
+	found := *release
+	if release.Assets == nil {
+		return &found
+	}
+
+	found.Assets = make([]Asset, len(release.Assets))
+
+	for i, asset := range release.Assets {
+		asset.Provider = index
+		found.Assets[i] = asset
+	}
+
+	return &found
+}
+
+func assetProvider(provider MultiProvider, asset Asset) (UpdaterProvider, error) {
+	if asset.Provider < 0 || asset.Provider >= len(provider.Providers) || provider.Providers[asset.Provider] == nil {
+		return nil, fmt.Errorf("provider %d of %s not found", asset.Provider, asset.Name)
+	}
+
+	return provider.Providers[asset.Provider], nil
+}
+
+func rewriteAssetURLs(release *Release, rewrite func(string) string) *Release {
+	if release == nil || rewrite == nil {
+		return release
+	}
+
+	rewritten := *release
+	rewritten.Assets = make([]Asset, len(release.Assets))
+
+	for i, asset := range release.Assets {
+		asset.URL = rewrite(asset.URL)
+		rewritten.Assets[i] = asset
+	}
+
+	return &rewritten
+}
+
+func validateMultiProvider(p MultiProvider) error {
+	if len(p.Providers) == 0 {
+		return fmt.Errorf("at least one provider is required")
+	}
+
+	for i, provider := range p.Providers {
+		if provider == nil {
+			return fmt.Errorf("provider %d is nil", i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubProvider struct {
+	release *Release
+	err     error
+	calls   *int
+}
+
+func (p stubProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
+	if p.calls != nil {
+		*p.calls++
+	}
+	return p.release, p.err
+}
+
+func (stubProvider) CacheRelease(r Release) error {
+	return nil
+}
+
+func (stubProvider) RestoreCacheRelease() (*Release, error) {
+	return nil, nil
+}
+
+func TestMultiFetchLastReleaseValidationError(t *testing.T) {
+	_, err := MultiProvider{}.FetchLastRelease(nil)
+	assert.Equal(t, "at least one provider is required", err.Error())
+
+	_, err = MultiProvider{Providers: []UpdaterProvider{nil}}.FetchLastRelease(nil)
+	assert.Equal(t, "provider 0 is nil", err.Error())
+}
+
+func TestMultiFetchLastReleaseFirstSuccess(t *testing.T) {
+	calls := 0
+	p := MultiProvider{
+		Providers: []UpdaterProvider{
+			stubProvider{err: fmt.Errorf("github integration error: 403")},
+			stubProvider{release: &Release{Name: "v0.1.1"}},
+			stubProvider{release: &Release{Name: "v0.1.2"}, calls: &calls},
+		},
+	}
+
+	actual, err := p.FetchLastRelease(nil)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v0.1.1", actual.Name)
+	assert.Equal(t, 0, calls)
+}
+
+func TestMultiFetchLastReleaseHighestVersion(t *testing.T) {
+	p := MultiProvider{
+		Strategy: HighestVersion,
+		Providers: []UpdaterProvider{
+			stubProvider{release: &Release{Name: "v0.1.1"}},
+			stubProvider{err: fmt.Errorf("github integration error: 403")},
+			stubProvider{release: &Release{Name: "v0.1.2"}},
+			stubProvider{},
+		},
+	}
+
+	actual, err := p.FetchLastRelease(nil)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v0.1.2", actual.Name)
+}
+
+func TestMultiFetchLastReleaseHighestVersionScheme(t *testing.T) {
+	p := MultiProvider{
+		Strategy: HighestVersion,
+		Scheme:   CalVer{},
+		Providers: []UpdaterProvider{
+			stubProvider{release: &Release{Name: "2024.10.1"}},
+			stubProvider{release: &Release{Name: "2024.9.3"}},
+		},
+	}
+
+	actual, err := p.FetchLastRelease(nil)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "2024.10.1", actual.Name)
+	assert.Equal(t, CalVer{}, p.VersionScheme())
+}
+
+func TestMultiFetchLastReleaseAllFailed(t *testing.T) {
+	p := MultiProvider{
+		Providers: []UpdaterProvider{
+			stubProvider{err: fmt.Errorf("github integration error: 403")},
+			stubProvider{err: fmt.Errorf("gitlab integration error: 500")},
+		},
+	}
+
+	_, err := p.FetchLastRelease(nil)
+	assert.Equal(t, "all providers failed: provider 0: github integration error: 403; "+
+		"provider 1: gitlab integration error: 500", err.Error())
+}
+
+func TestMultiFetchLastReleaseNoRelease(t *testing.T) {
+	p := MultiProvider{Providers: []UpdaterProvider{stubProvider{}}}
+
+	actual, err := p.FetchLastRelease(nil)
+	assert.Nil(t, err, err)
+	assert.Nil(t, actual)
+}
+
+func TestMultiFetchLastReleaseRewriteURL(t *testing.T) {
+	release := &Release{
+		Name: "v0.1.2",
+		Assets: []Asset{
+			{Name: "14-bis_Linux_x86_64.tar.gz", URL: "https://github.com/santos/14-bis/releases/download/v0.1.2/14-bis.tar.gz"},
+			{Name: "checksums.txt", URL: "https://github.com/santos/14-bis/releases/download/v0.1.2/checksums.txt"},
+		},
+	}
+	p := MultiProvider{
+		Providers: []UpdaterProvider{stubProvider{release: release}},
+		RewriteURL: PrefixRewriter(map[string]string{
+			"https://github.com/": "https://mirror.corp.com/github/",
+		}),
+	}
+
+	actual, err := p.FetchLastRelease(nil)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "https://mirror.corp.com/github/santos/14-bis/releases/download/v0.1.2/14-bis.tar.gz",
+		actual.Assets[0].URL)
+	assert.Equal(t, "https://mirror.corp.com/github/santos/14-bis/releases/download/v0.1.2/checksums.txt",
+		actual.Assets[1].URL)
+	assert.Equal(t, "https://github.com/santos/14-bis/releases/download/v0.1.2/14-bis.tar.gz",
+		release.Assets[0].URL)
+}
+
+type stubFetcher struct {
+	stubProvider
+	releases []*Release
+}
+
+func (p stubFetcher) FetchReleases(client HTTPClientPlugin) ([]*Release, error) {
+	return p.releases, p.err
+}
+
+func TestMultiFetchReleasesFirstSuccess(t *testing.T) {
+	p := MultiProvider{
+		Providers: []UpdaterProvider{
+			stubFetcher{stubProvider: stubProvider{err: fmt.Errorf("github integration error: 403")}},
+			stubFetcher{releases: []*Release{{Name: "v0.1.1"}, {Name: "v0.1.0"}}},
+			stubProvider{release: &Release{Name: "v0.1.2"}},
+		},
+	}
+
+	actual, err := p.FetchReleases(nil)
+	assert.Nil(t, err, err)
+	assert.Equal(t, []*Release{{Name: "v0.1.1"}, {Name: "v0.1.0"}}, actual)
+}
+
+func TestMultiFetchReleasesHighestVersion(t *testing.T) {
+	p := MultiProvider{
+		Strategy: HighestVersion,
+		Providers: []UpdaterProvider{
+			stubFetcher{releases: []*Release{{Name: "v0.1.1", Description: "github"}, {Name: "v0.1.0"}}},
+			stubFetcher{releases: []*Release{{Name: "v0.1.1", Description: "mirror"}}},
+			stubProvider{release: &Release{Name: "v0.1.2"}},
+			stubProvider{},
+		},
+		RewriteURL: PrefixRewriter(map[string]string{"https://github.com/": "https://mirror.corp.com/"}),
+	}
+
+	actual, err := p.FetchReleases(nil)
+	assert.Nil(t, err, err)
+	assert.Equal(t, []*Release{
+		{Name: "v0.1.1", Description: "github", Assets: []Asset{}},
+		{Name: "v0.1.0", Assets: []Asset{}},
+		{Name: "v0.1.2", Assets: []Asset{}},
+	}, actual)
+}
+
+func TestMultiFetchReleasesAllFailed(t *testing.T) {
+	p := MultiProvider{
+		Providers: []UpdaterProvider{
+			stubFetcher{stubProvider: stubProvider{err: fmt.Errorf("github integration error: 403")}},
+			stubProvider{err: fmt.Errorf("gitlab integration error: 500")},
+		},
+	}
+
+	_, err := p.FetchReleases(nil)
+	assert.Equal(t, "all providers failed: provider 0: github integration error: 403; "+
+		"provider 1: gitlab integration error: 500", err.Error())
+
+	_, err = MultiProvider{}.FetchReleases(nil)
+	assert.Equal(t, "at least one provider is required", err.Error())
+}
+
+type stubVerifier struct {
+	stubProvider
+	verified *[]string
+}
+
+func (p stubVerifier) VerifyAsset(asset Asset, path string) error {
+	*p.verified = append(*p.verified, asset.Name)
+	return nil
+}
+
+func (p stubVerifier) DownloadClient(client HTTPClientPlugin, release *Release) HTTPClientPlugin {
+	return &registryClient{client: client, username: "santos"}
+}
+
+func TestMultiVerifyAssetAndDownloadClient(t *testing.T) {
+	var verified []string
+	p := MultiProvider{
+		Strategy: HighestVersion,
+		Providers: []UpdaterProvider{
+			stubProvider{release: &Release{Name: "v0.1.1", Assets: []Asset{{Name: "14-bis.zip"}}}},
+			stubVerifier{
+				stubProvider: stubProvider{release: &Release{Name: "v0.1.2", Assets: []Asset{{Name: "14-bis.dmg"}}}},
+				verified:     &verified,
+			},
+		},
+	}
+
+	actual, err := p.FetchLastRelease(nil)
+	assert.Nil(t, err, err)
+	assert.Equal(t, 1, actual.Assets[0].Provider)
+
+	assert.Nil(t, p.VerifyAsset(actual.Assets[0], "14-bis.dmg"))
+	assert.Equal(t, []string{"14-bis.dmg"}, verified)
+	assert.Equal(t, &registryClient{username: "santos"}, p.DownloadClient(nil, actual))
+
+	other := &Release{Name: "v0.1.1", Assets: []Asset{{Name: "14-bis.zip"}}}
+	assert.Equal(t, "provider 0 is not able to verify 14-bis.zip", p.VerifyAsset(other.Assets[0], "").Error())
+	assert.Nil(t, p.DownloadClient(nil, other))
+
+	unknown := Asset{Name: "14-bis.zip", Provider: 2}
+	assert.Equal(t, "provider 2 of 14-bis.zip not found", p.VerifyAsset(unknown, "").Error())
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// ReleaseNotes gathers the releases between two versions, newest first, so users
+// see every change they are skipping over when they jump several versions.
+type ReleaseNotes []*Release
+
+var (
+	htmlTagRegex        = regexp.MustCompile(`<[^>]+>`)
+	markdownImageRegex  = regexp.MustCompile(`!\[([^\]]*)\]\(([^)]*)\)`)
+	markdownLinkRegex   = regexp.MustCompile(`\[([^\]]+)\]\(([^)]*)\)`)
+	markdownHeaderRegex = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+`)
+	markdownEmphRegex   = regexp.MustCompile("(\\*\\*|__|\\*|`)([^*`]+)(\\*\\*|__|\\*|`)")
+	markdownFenceRegex  = regexp.MustCompile("(?m)^\\s*```.*$\\n?")
+)
+
+// CollectReleaseNotes returns the releases within the interval (from, to] according
+// to scheme, newest first. Drafts and releases that don't follow scheme are left out.
+// SemVer is used when scheme is nil.
+func CollectReleaseNotes(releases []*Release, from, to string, scheme VersionScheme) ReleaseNotes {
+	lower := &Release{Name: from}
+	upper := &Release{Name: to}
+
+	notes := ReleaseNotes{}
+	for _, release := range releases {
+		if release.Draft || !followsScheme(release, scheme) {
+			continue
+		}
+
+		if release.CompareWith(lower, scheme) == 1 && release.CompareWith(upper, scheme) <= 0 {
+			notes = append(notes, release)
+		}
+	}
+
+	SortReleases(notes, scheme)
+
+	return notes
+}
+
+// Mandatory tells whether updating is mandatory for a program running currver because
+// of any of the releases gathered, e.g. a critical release skipped over on the way to
+// the last one. See Release.Mandatory.
+func (notes ReleaseNotes) Mandatory(currver string, scheme VersionScheme) bool {
+	for _, release := range notes {
+		if release.Mandatory(currver, scheme) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Markdown renders the notes as a Markdown document with a section per release.
+func (notes ReleaseNotes) Markdown() string {
+	var builder strings.Builder
+
+	for i, release := range notes {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		fmt.Fprintf(&builder, "## %s\n", releaseTitle(release))
+
+		if description := strings.TrimSpace(release.Description); description != "" {
+			builder.WriteString("\n")
+			builder.WriteString(description)
+			builder.WriteString("\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// Text renders the notes as plain text. Markdown and HTML markup of the
+// descriptions is removed, and links are written as text followed by the URL.
+func (notes ReleaseNotes) Text() string {
+	var builder strings.Builder
+
+	for i, release := range notes {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		title := releaseTitle(release)
+		fmt.Fprintf(&builder, "%s\n%s\n", title, strings.Repeat("=", len(title)))
+
+		if description := plainText(release.Description); description != "" {
+			builder.WriteString("\n")
+			builder.WriteString(description)
+			builder.WriteString("\n")
+		}
+	}
+
+	return builder.String()
+}
+
+func releaseTitle(release *Release) string {
+	if release.ReleasedAt.IsZero() {
+		return release.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", release.Name, release.ReleasedAt.Format("2006-01-02"))
+}
+
+func plainText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = htmlTagRegex.ReplaceAllString(s, "")
+	s = markdownFenceRegex.ReplaceAllString(s, "")
+	s = markdownImageRegex.ReplaceAllString(s, "$1")
+	s = markdownLinkRegex.ReplaceAllString(s, "$1 ($2)")
+	s = markdownHeaderRegex.ReplaceAllString(s, "")
+	s = markdownEmphRegex.ReplaceAllString(s, "$2")
+
+	return strings.TrimSpace(html.UnescapeString(s))
+}
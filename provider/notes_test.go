@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectReleaseNotes(t *testing.T) {
+	releases := []*Release{
+		{Name: "v1.2.0"}, {Name: "v1.6.0"}, {Name: "v1.3.0"}, {Name: "v1.7.0"},
+		{Name: "v1.5.0", Draft: true}, {Name: "v1.4.1"}, {Name: "v1.1.0"},
+	}
+
+	notes := CollectReleaseNotes(releases, "1.2.0", "v1.6.0", nil)
+	assert.Equal(t, ReleaseNotes{{Name: "v1.6.0"}, {Name: "v1.4.1"}, {Name: "v1.3.0"}}, notes)
+}
+
+func TestCollectReleaseNotesEmpty(t *testing.T) {
+	notes := CollectReleaseNotes([]*Release{{Name: "r10"}}, "r10", "r10", Monotonic{})
+	assert.Equal(t, ReleaseNotes{}, notes)
+	assert.Empty(t, notes.Markdown())
+	assert.Empty(t, notes.Text())
+}
+
+func TestReleaseNotesMandatory(t *testing.T) {
+	releases := []*Release{
+		{Name: "v1.2.0"}, {Name: "v1.6.0"}, {Name: "v1.3.0", Critical: true},
+		{Name: "v1.4.1", MinSupportedVersion: "v1.1.0"}, {Name: "v1.1.0", Critical: true},
+	}
+
+	notes := CollectReleaseNotes(releases, "1.2.0", "v1.6.0", nil)
+	assert.False(t, notes[0].Mandatory("1.2.0", nil))
+	assert.True(t, notes.Mandatory("1.2.0", nil))
+
+	notes = CollectReleaseNotes(releases, "1.3.0", "v1.6.0", nil)
+	assert.False(t, notes.Mandatory("1.3.0", nil))
+	assert.False(t, ReleaseNotes{}.Mandatory("1.3.0", nil))
+}
+
+func TestReleaseNotesMarkdown(t *testing.T) {
+	notes := ReleaseNotes{
+		{
+			Name:        "v1.6.0",
+			Description: "### Breaking changes\n\n- Config key `server` renamed to `host`.\n",
+			ReleasedAt:  time.Date(2023, 3, 9, 14, 11, 18, 0, time.UTC),
+		},
+		{Name: "v1.5.0"},
+	}
+
+	expected := "## v1.6.0 (2023-03-09)\n\n### Breaking changes\n\n- Config key `server` renamed to `host`.\n" +
+		"\n## v1.5.0\n"
+	assert.Equal(t, expected, notes.Markdown())
+}
+
+func TestReleaseNotesText(t *testing.T) {
+	notes := ReleaseNotes{
+		{
+			Name: "v1.6.0",
+			Description: "### Breaking changes\r\n\r\n- Config key **server** renamed to `host`.\r\n" +
+				"- See [docs](https://docs.corp.com) ![logo](logo.png)\r\n```yaml\r\nhost: x\r\n```\r\n",
+			ReleasedAt: time.Date(2023, 3, 9, 14, 11, 18, 0, time.UTC),
+		},
+		{Name: "0.1.10", Description: "<p>Bug fix &amp; <b>speed</b>.</p>"},
+	}
+
+	expected := "v1.6.0 (2023-03-09)\n===================\n\nBreaking changes\n\n" +
+		"- Config key server renamed to host.\n- See docs (https://docs.corp.com) logo\nhost: x\n" +
+		"\n0.1.10\n======\n\nBug fix & speed.\n"
+	assert.Equal(t, expected, notes.Text())
+}
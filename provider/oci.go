@@ -0,0 +1,316 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	ociTitleAnnotation      = "org.opencontainers.image.title"
+	ociDescAnnotation       = "org.opencontainers.image.description"
+	ociCreatedAnnotation    = "org.opencontainers.image.created"
+)
+
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// OCIProvider is a provider for getting releases from an OCI registry.
+//
+// Every tag of Repository that follows Scheme (SemVer by default) is a
+// release, and the layers of its manifest are the release assets. Username and Password
+// are only used when the registry demands authentication.
+type OCIProvider struct {
+	Host       string
+	Port       uint
+	Ssl        bool
+	Repository string
+	Username   string
+	Password   string
+	Timeout    time.Duration
+	Scheme     VersionScheme
+}
+
+// OCITagList is a representation - in JSON form - of what an OCI
+// registry returns when the tags of a repository are listed.
+type OCITagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// OCIManifest is a representation - in JSON form - of what an OCI
+// registry returns when the manifest of a tag is requested.
+type OCIManifest struct {
+	MediaType   string            `json:"mediaType"`
+	Annotations map[string]string `json:"annotations"`
+	Layers      []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int64             `json:"size"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+func (provider OCIProvider) FetchLastRelease(client HTTPClientPlugin) (*Release, error) {
+	initOCIProvider(&provider)
+	err := validateOCIProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	client = &registryClient{client: client, username: provider.Username, password: provider.Password}
+	tags, err := fetchOCIVersionTags(provider, client)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := provider.VersionScheme()
+	var lastTag string
+	for _, tag := range tags {
+		if lastTag == "" || scheme.Compare(lastTag, tag) == -1 {
+			lastTag = tag
+		}
+	}
+
+	if lastTag == "" {
+		return nil, nil
+	}
+
+	manifest, err := fetchOCIManifest(provider, client, lastTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertOCIToBase(provider, lastTag, manifest), nil
+}
+
+// FetchReleases fetches the manifest of every tag that follows the version
+// scheme, so it costs one request per release.
+func (provider OCIProvider) FetchReleases(client HTTPClientPlugin) ([]*Release, error) {
+	initOCIProvider(&provider)
+	err := validateOCIProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	client = &registryClient{client: client, username: provider.Username, password: provider.Password}
+	tags, err := fetchOCIVersionTags(provider, client)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]*Release, len(tags))
+	for i, tag := range tags {
+		manifest, err := fetchOCIManifest(provider, client, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		releases[i] = convertOCIToBase(provider, tag, manifest)
+	}
+
+	return releases, nil
+}
+
+// DownloadClient authenticates the download of the blobs the same way as the
+// requests to the registry API.
+func (provider OCIProvider) DownloadClient(client HTTPClientPlugin, _ *Release) HTTPClientPlugin {
+	return &registryClient{client: client, username: provider.Username, password: provider.Password}
+}
+
+func (provider OCIProvider) VersionScheme() VersionScheme {
+	return schemeOrDefault(provider.Scheme)
+}
+
+func (OCIProvider) CacheRelease(r Release) error {
+	return serializeRelease(&r)
+}
+
+func (OCIProvider) RestoreCacheRelease() (*Release, error) {
+	return deserializeRelease()
+}
+
+// fetchOCIVersionTags returns the tags of the repository that follow the version scheme.
+func fetchOCIVersionTags(p OCIProvider, client HTTPClientPlugin) ([]string, error) {
+	tags, err := fetchOCITags(p, client)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := p.VersionScheme()
+	versions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if scheme.Validate(tag) == nil {
+			versions = append(versions, tag)
+		}
+	}
+
+	return versions, nil
+}
+
+func fetchOCITags(p OCIProvider, client HTTPClientPlugin) ([]string, error) {
+	var tags []string
+	srvURL := fmt.Sprintf("%s/tags/list", buildOCIServiceURL(p))
+
+	for srvURL != "" {
+		tagList, next, err := fetchOCITagsPage(p, client, srvURL)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tagList.Tags...)
+		srvURL = next
+	}
+
+	return tags, nil
+}
+
+func fetchOCITagsPage(p OCIProvider, client HTTPClientPlugin, srvURL string) (*OCITagList, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srvURL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("oci integration error: %d", resp.StatusCode)
+	}
+
+	tagList := &OCITagList{}
+	err = json.NewDecoder(resp.Body).Decode(tagList)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if match := linkNextRegex.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+		ref, e := url.Parse(match[1])
+		if e != nil {
+			return nil, "", e
+		}
+		next = req.URL.ResolveReference(ref).String()
+	}
+
+	return tagList, next, nil
+}
+
+func fetchOCIManifest(p OCIProvider, client HTTPClientPlugin, tag string) (*OCIManifest, error) {
+	srvURL := fmt.Sprintf("%s/manifests/%s", buildOCIServiceURL(p), tag)
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srvURL, nil)
+	req.Header.Set("Accept", strings.Join([]string{ociManifestMediaType, dockerManifestMediaType}, ", "))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oci integration error: %d", resp.StatusCode)
+	}
+
+	manifest := &OCIManifest{}
+	err = json.NewDecoder(resp.Body).Decode(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func buildOCIServiceURL(p OCIProvider) string {
+	protocol := "http"
+	if p.Ssl {
+		protocol += "s"
+	}
+
+	return fmt.Sprintf("%s://%s:%d/v2/%s", protocol, p.Host, p.Port, p.Repository)
+}
+
+func convertOCIToBase(p OCIProvider, tag string, m *OCIManifest) *Release {
+	t := Release{
+		Name:        tag,
+		Description: m.Annotations[ociDescAnnotation],
+	}
+
+	if created, err := time.Parse(time.RFC3339, m.Annotations[ociCreatedAnnotation]); err == nil {
+		t.ReleasedAt = created
+	}
+
+	applyReleaseMetadata(&t)
+	if critical, found := m.Annotations[ociCriticalAnnotation]; found {
+		setReleaseMetadata(&t, "critical", critical)
+	}
+	if version, found := m.Annotations[ociMinSupportedVersionAnnotation]; found {
+		setReleaseMetadata(&t, "min_supported_version", version)
+	}
+	if rollout, found := m.Annotations[ociRolloutAnnotation]; found {
+		setReleaseMetadata(&t, "rollout", rollout)
+	}
+
+	size := len(m.Layers)
+	t.Assets = make([]Asset, size)
+	baseURL := buildOCIServiceURL(p)
+
+	for i, layer := range m.Layers {
+		name := layer.Annotations[ociTitleAnnotation]
+		if name == "" {
+			name = layer.Digest[strings.Index(layer.Digest, ":")+1:]
+		}
+
+		t.Assets[i] = Asset{
+			Name:   name,
+			URL:    fmt.Sprintf("%s/blobs/%s", baseURL, layer.Digest),
+			Digest: layer.Digest,
+		}
+	}
+
+	return &t
+}
+
+func validateOCIProvider(p OCIProvider) error {
+	switch {
+	case p.Host == "":
+		return fmt.Errorf("host is required")
+	case p.Port <= 0:
+		return fmt.Errorf("port must be > 0")
+	case p.Repository == "":
+		return fmt.Errorf("repository is required")
+	default:
+		return nil
+	}
+}
+
+func initOCIProvider(p *OCIProvider) {
+	const httpPort = 80
+	const httpsPort = 443
+	const timeout = time.Second * 30
+
+	if p.Port == 0 {
+		if p.Ssl {
+			p.Port = httpsPort
+		} else {
+			p.Port = httpPort
+		}
+	} else {
+		p.Ssl = p.Port == httpsPort
+	}
+
+	if p.Timeout == 0 {
+		p.Timeout = timeout
+	}
+}
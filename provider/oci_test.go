@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRegistryServer starts an in-process stand-in of a registry that serves
+// the tags and manifests of the repository tools/14-bis.
+func newRegistryServer(t *testing.T, token string) (*httptest.Server, OCIProvider) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if token == "" || r.Header.Get("Authorization") == "Bearer "+token {
+			return true
+		}
+
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Bearer realm="%s/token",service="registry.test",scope="repository:tools/14-bis:pull"`, srv.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "santos" || pass != "dumont" ||
+			r.URL.Query().Get("service") != "registry.test" ||
+			r.URL.Query().Get("scope") != "repository:tools/14-bis:pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, `{"token":"%s"}`, token)
+	})
+
+	mux.HandleFunc("/v2/tools/14-bis/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+
+		if r.URL.Query().Get("last") == "" {
+			w.Header().Set("Link", `</v2/tools/14-bis/tags/list?n=3&last=v0.1.2>; rel="next"`)
+			fmt.Fprint(w, `{"name":"tools/14-bis","tags":["latest","v0.1.0","v0.1.2"]}`)
+			return
+		}
+		fmt.Fprint(w, `{"name":"tools/14-bis","tags":["v0.1.10","nightly"]}`)
+	})
+
+	mux.HandleFunc("/v2/tools/14-bis/blobs/", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+		fmt.Fprint(w, "foo")
+	})
+
+	mux.HandleFunc("/v2/tools/14-bis/manifests/", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(w, r) {
+			return
+		}
+
+		assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.manifest.v1+json")
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		fmt.Fprint(w, `{
+			"mediaType": "application/vnd.oci.image.manifest.v1+json",
+			"annotations": {
+				"org.opencontainers.image.description": "Bug fix.",
+				"org.opencontainers.image.created": "2023-03-09T14:11:18Z"
+			},
+			"layers": [
+				{
+					"mediaType": "application/vnd.oci.image.layer.v1.tar",
+					"digest": "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+					"size": 3,
+					"annotations": {"org.opencontainers.image.title": "14-bis_Linux_x86_64.tar.gz"}
+				},
+				{
+					"mediaType": "application/vnd.oci.image.layer.v1.tar",
+					"digest": "sha256:fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9",
+					"size": 3
+				}
+			]
+		}`)
+	})
+
+	addr := srv.Listener.Addr().(*net.TCPAddr)
+	host, port, _ := net.SplitHostPort(addr.String())
+	iport, _ := strconv.Atoi(port)
+
+	return srv, OCIProvider{Host: host, Port: uint(iport), Repository: "tools/14-bis"}
+}
+
+func TestOCIFetchLastReleaseValidationError(t *testing.T) {
+	m := new(mockDecorator)
+	p := OCIProvider{}
+
+	_, err := p.FetchLastRelease(m)
+	assert.Equal(t, "host is required", err.Error())
+}
+
+func TestOCIFetchLastRelease(t *testing.T) {
+	srv, p := newRegistryServer(t, "")
+
+	actual, err := p.FetchLastRelease(&HTTPClientDecorator{Client: *srv.Client()})
+	assert.Nil(t, err, err)
+
+	base := fmt.Sprintf("http://%s/v2/tools/14-bis/blobs/", srv.Listener.Addr().String())
+	assert.Equal(t, "v0.1.10", actual.Name)
+	assert.Equal(t, "Bug fix.", actual.Description)
+	assert.Equal(t, time.Date(2023, 3, 9, 14, 11, 18, 0, time.UTC), actual.ReleasedAt)
+	assert.Equal(t, []Asset{
+		{
+			Name:   "14-bis_Linux_x86_64.tar.gz",
+			URL:    base + "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+			Digest: "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		},
+		{
+			Name:   "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9",
+			URL:    base + "sha256:fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9",
+			Digest: "sha256:fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9",
+		},
+	}, actual.Assets)
+}
+
+func TestOCIFetchLastReleaseSchemeSkipsTags(t *testing.T) {
+	srv, p := newRegistryServer(t, "")
+	p.Scheme = Monotonic{}
+
+	actual, err := p.FetchLastRelease(&HTTPClientDecorator{Client: *srv.Client()})
+	assert.Nil(t, err, err)
+	assert.Nil(t, actual)
+	assert.Equal(t, Monotonic{}, p.VersionScheme())
+}
+
+func TestOCIFetchReleases(t *testing.T) {
+	srv, p := newRegistryServer(t, "")
+
+	actual, err := p.FetchReleases(&HTTPClientDecorator{Client: *srv.Client()})
+	assert.Nil(t, err, err)
+	assert.Len(t, actual, 3)
+
+	for i, name := range []string{"v0.1.0", "v0.1.2", "v0.1.10"} {
+		assert.Equal(t, name, actual[i].Name)
+		assert.Len(t, actual[i].Assets, 2)
+	}
+}
+
+func TestConvertOCIToBaseMetadata(t *testing.T) {
+	p := OCIProvider{Host: "ghcr.io", Port: 443, Ssl: true, Repository: "tools/14-bis"}
+	m := &OCIManifest{Annotations: map[string]string{
+		"io.caravela.critical":              "true",
+		"io.caravela.min-supported-version": "v0.1.2",
+		"io.caravela.rollout":               "5",
+	}}
+
+	actual := convertOCIToBase(p, "v0.1.10", m)
+	assert.True(t, actual.Critical)
+	assert.Equal(t, "v0.1.2", actual.MinSupportedVersion)
+	assert.Equal(t, 5, actual.Rollout)
+
+	m.Annotations = map[string]string{"org.opencontainers.image.description": "<!-- caravela: critical: true -->"}
+	actual = convertOCIToBase(p, "v0.1.10", m)
+	assert.True(t, actual.Critical)
+	assert.Empty(t, actual.MinSupportedVersion)
+}
+
+func TestOCIFetchReleasesValidationError(t *testing.T) {
+	_, err := OCIProvider{}.FetchReleases(new(mockDecorator))
+	assert.Equal(t, "host is required", err.Error())
+}
+
+func TestOCIFetchLastReleaseTokenAuth(t *testing.T) {
+	srv, p := newRegistryServer(t, "s3cr3t")
+	p.Username = "santos"
+	p.Password = "dumont"
+
+	actual, err := p.FetchLastRelease(&HTTPClientDecorator{Client: *srv.Client()})
+	assert.Nil(t, err, err)
+	assert.Equal(t, "v0.1.10", actual.Name)
+}
+
+func TestOCIDownloadClientTokenAuth(t *testing.T) {
+	srv, p := newRegistryServer(t, "s3cr3t")
+	p.Username = "santos"
+	p.Password = "dumont"
+
+	client := &HTTPClientDecorator{Client: *srv.Client()}
+	actual, err := p.FetchLastRelease(client)
+	assert.Nil(t, err, err)
+
+	req, _ := http.NewRequest(http.MethodGet, actual.Assets[0].URL, nil)
+	resp, err := client.Do(req)
+	assert.Nil(t, err, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodGet, actual.Assets[0].URL, nil)
+	resp, err = p.DownloadClient(client, actual).Do(req)
+	assert.Nil(t, err, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "foo", string(body))
+}
+
+func TestOCIFetchLastReleaseTokenAuthDenied(t *testing.T) {
+	srv, p := newRegistryServer(t, "s3cr3t")
+	p.Username = "santos"
+	p.Password = "wrong"
+
+	_, err := p.FetchLastRelease(&HTTPClientDecorator{Client: *srv.Client()})
+	assert.Equal(t, "registry authentication error: 403", err.Error())
+}
+
+func TestOCIFetchLastReleaseNotFound(t *testing.T) {
+	srv, p := newRegistryServer(t, "")
+	p.Repository = "tools/unknown"
+
+	_, err := p.FetchLastRelease(&HTTPClientDecorator{Client: *srv.Client()})
+	assert.Equal(t, "oci integration error: 404", err.Error())
+}
+
+func TestBuildOCIServiceURL(t *testing.T) {
+	p := OCIProvider{Host: "harbor.corp.com", Port: 443, Ssl: true, Repository: "tools/14-bis"}
+	assert.Equal(t, "https://harbor.corp.com:443/v2/tools/14-bis", buildOCIServiceURL(p))
+}
+
+func TestValidateOCIProvider(t *testing.T) {
+	p := OCIProvider{Host: "harbor.corp.com", Port: 0, Repository: "tools/14-bis"}
+	assert.Equal(t, "port must be > 0", validateOCIProvider(p).Error())
+
+	p.Port = 443
+	p.Repository = ""
+	assert.Equal(t, "repository is required", validateOCIProvider(p).Error())
+
+	p.Repository = "tools/14-bis"
+	assert.Nil(t, validateOCIProvider(p))
+}
+
+func TestInitOCIProvider(t *testing.T) {
+	p := OCIProvider{Ssl: true}
+	initOCIProvider(&p)
+
+	assert.Equal(t, uint(443), p.Port)
+	assert.Equal(t, time.Second*30, p.Timeout)
+
+	p = OCIProvider{Port: 443}
+	initOCIProvider(&p)
+
+	assert.Equal(t, uint(443), p.Port)
+	assert.True(t, p.Ssl)
+
+	p = OCIProvider{Port: 5000, Ssl: true}
+	initOCIProvider(&p)
+
+	assert.Equal(t, uint(5000), p.Port)
+	assert.False(t, p.Ssl)
+}
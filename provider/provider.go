@@ -27,3 +27,32 @@ type UpdaterProvider interface {
 	// It returns nil if no release has been cached yet.
 	RestoreCacheRelease() (*Release, error)
 }
+
+// AssetVerifier is implemented by providers that are able to check the
+// authenticity of a downloaded asset, e.g. through its signature.
+type AssetVerifier interface {
+	// VerifyAsset checks the file at path, downloaded from asset.
+	VerifyAsset(asset Asset, path string) error
+}
+
+// AssetDownloader is implemented by providers whose assets are only downloaded
+// with the credentials of the provider, e.g. a private registry.
+type AssetDownloader interface {
+	// DownloadClient decorates client so it is able to download the assets of release.
+	DownloadClient(client HTTPClientPlugin, release *Release) HTTPClientPlugin
+}
+
+// ReleasesFetcher is implemented by providers that are able to list every
+// release of a project, so it can be picked by a version scheme other than
+// the one of the provider.
+type ReleasesFetcher interface {
+	// FetchReleases queries provider for the releases of a project.
+	FetchReleases(client HTTPClientPlugin) ([]*Release, error)
+}
+
+// SchemeProvider is implemented by providers whose releases are compared
+// according to a version scheme.
+type SchemeProvider interface {
+	// VersionScheme returns the version scheme of the releases.
+	VersionScheme() VersionScheme
+}
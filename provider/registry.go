@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// registryClient decorates an HTTPClientPlugin with the authentication flow
+// described by the registry token specification. When the registry answers
+// with 401 and a Bearer challenge, a token is requested to the realm of the
+// challenge and the request is sent again carrying that token.
+type registryClient struct {
+	client   HTTPClientPlugin
+	username string
+	password string
+	token    string
+	basic    bool
+}
+
+// RegistryToken is a representation - in JSON form - of what a registry
+// authorization service returns when a token is requested.
+type RegistryToken struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+func (c *registryClient) Do(req *http.Request) (*http.Response, error) {
+	c.authorize(req)
+	resp, err := c.client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if resp.Body != nil {
+		resp.Body.Close()
+	}
+
+	err = c.authenticate(req.Context(), challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	c.authorize(retry)
+
+	return c.client.Do(retry)
+}
+
+func (c *registryClient) authorize(req *http.Request) {
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.basic:
+		req.SetBasicAuth(c.username, c.password)
+	}
+}
+
+func (c *registryClient) authenticate(ctx context.Context, challenge string) error {
+	scheme, params := parseAuthChallenge(challenge)
+
+	switch scheme {
+	case "basic":
+		if c.username == "" {
+			return fmt.Errorf("registry requires credentials")
+		}
+		c.basic = true
+		return nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, params)
+		if err != nil {
+			return err
+		}
+		c.token = token
+		return nil
+	default:
+		return fmt.Errorf("unsupported registry authentication challenge %q", challenge)
+	}
+}
+
+func (c *registryClient) fetchToken(ctx context.Context, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid registry authentication realm %q", params["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value, ok := params[key]; ok {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry authentication error: %d", resp.StatusCode)
+	}
+
+	token := &RegistryToken{}
+	err = json.NewDecoder(resp.Body).Decode(token)
+	if err != nil {
+		return "", err
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	} else if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+
+	return "", fmt.Errorf("registry authentication returned no token")
+}
+
+// parseAuthChallenge splits a WWW-Authenticate header into its lower case
+// scheme and parameters, e.g. Bearer realm="https://auth",service="registry".
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	challenge = strings.TrimSpace(challenge)
+	params := make(map[string]string)
+
+	i := strings.IndexByte(challenge, ' ')
+	if i < 0 {
+		return strings.ToLower(challenge), params
+	}
+
+	scheme := strings.ToLower(challenge[:i])
+	rest := challenge[i+1:]
+
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end+1:]
+			}
+		}
+
+		params[key] = strings.TrimSpace(value)
+	}
+
+	return scheme, params
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(
+		`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",` +
+			`scope="repository:samalba/my-app:pull,push"`)
+
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:samalba/my-app:pull,push",
+	}, params)
+
+	scheme, params = parseAuthChallenge(`Basic realm=Registry`)
+	assert.Equal(t, "basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "Registry"}, params)
+}
+
+func TestRegistryClientBasicAuth(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		_, _, ok := req.BasicAuth()
+		return !ok
+	})).Return(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Header:     http.Header{"Www-Authenticate": []string{`Basic realm="Registry"`}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(``))),
+	}, nil)
+	m.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		user, pass, ok := req.BasicAuth()
+		return ok && user == "santos" && pass == "dumont"
+	})).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(``))),
+	}, nil)
+
+	client := &registryClient{client: m, username: "santos", password: "dumont"}
+	req, _ := http.NewRequest(http.MethodGet, "http://registry.test/v2/", nil)
+	resp, err := client.Do(req)
+
+	assert.Nil(t, err, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	m.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestRegistryClientBasicAuthWithoutCredentials(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Header:     http.Header{"Www-Authenticate": []string{`Basic realm="Registry"`}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(``))),
+	}, nil)
+
+	client := &registryClient{client: m}
+	req, _ := http.NewRequest(http.MethodGet, "http://registry.test/v2/", nil)
+	_, err := client.Do(req)
+
+	assert.Equal(t, "registry requires credentials", err.Error())
+}
+
+func TestRegistryClientUnsupportedChallenge(t *testing.T) {
+	m := new(mockDecorator)
+	m.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Header:     http.Header{"Www-Authenticate": []string{`Negotiate`}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(``))),
+	}, nil)
+
+	client := &registryClient{client: m}
+	req, _ := http.NewRequest(http.MethodGet, "http://registry.test/v2/", nil)
+	_, err := client.Do(req)
+
+	assert.Equal(t, `unsupported registry authentication challenge "Negotiate"`, err.Error())
+}
@@ -3,14 +3,48 @@ package provider
 import "time"
 
 // Release is a structured data type that abstracts the release of a project.
+//
+// Name is the version of the release. Tag is set when the release was selected
+// through a TagFilter and holds the original tag, e.g. cli/v1.4.0. Prerelease and
+// Draft are set when the provider flags the release as such.
+//
+// Critical and MinSupportedVersion tell whether updating to the release is
+// mandatory, e.g. because older versions have a known vulnerability. See Mandatory.
+// Rollout, when set, is the percentage of installations the release is offered to,
+// RolloutPaused for none. See Eligible.
+//
+// CacheKey is only set on a cached release and tells the filter it was picked with,
+// e.g. the channel, so it is not restored for another one.
 type Release struct {
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	ReleasedAt  time.Time `json:"releasedAt"`
-	Assets      []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"assets"`
+	Name                string    `json:"name"`
+	Tag                 string    `json:"tag,omitempty"`
+	Description         string    `json:"description"`
+	ReleasedAt          time.Time `json:"releasedAt"`
+	Prerelease          bool      `json:"prerelease,omitempty"`
+	Draft               bool      `json:"draft,omitempty"`
+	Critical            bool      `json:"critical,omitempty"`
+	MinSupportedVersion string    `json:"minSupportedVersion,omitempty"`
+	Rollout             int       `json:"rollout,omitempty"`
+	Assets              []Asset   `json:"assets"`
+	CacheKey            string    `json:"cacheKey,omitempty"`
+}
+
+// Asset is a file attached to a release.
+//
+// Digest is optional and, when set, holds the content digest of the file
+// in the form algorithm:hex (e.g. sha256:2c26b4...). OS and Arch, when set,
+// tell the platform (in GOOS and GOARCH terms) the file was built for.
+// Signature is checked by providers that implement AssetVerifier. Provider is the
+// index, within MultiProvider.Providers, of the provider the file was found on.
+type Asset struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Digest    string `json:"digest,omitempty"`
+	Size      int64  `json:"size,omitempty"`
+	OS        string `json:"os,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Provider  int    `json:"provider,omitempty"`
 }
 
 // Comparator is an interface that provides methods for comparing two releases.
@@ -18,8 +52,21 @@ type Comparator interface {
 	CompareTo(r2 *Release) int
 }
 
-// CompareTo compares release r1 with release r2.
+// CompareTo compares release r1 with release r2. Names that are not semantic
+// versions are compared lexically, see CompareWith to compare them by scheme.
 // It returns 1, 0 or -1 if it is greater, equal or lesser.
 func (r1 *Release) CompareTo(r2 *Release) int {
 	return compareVersions(r1.Name, r2.Name)
 }
+
+// CompareWith compares release r1 with release r2 according to scheme.
+// SemVer is used when scheme is nil.
+// It returns 1, 0 or -1 if it is greater, equal or lesser.
+func (r1 *Release) CompareWith(r2 *Release, scheme VersionScheme) int {
+	return schemeOrDefault(scheme).Compare(r1.Name, r2.Name)
+}
+
+// Version parses the release name as a semantic version.
+func (r1 *Release) Version() (Version, error) {
+	return ParseVersion(r1.Name)
+}
@@ -12,3 +12,11 @@ func TestReleaseCompareTo(t *testing.T) {
 
 	assert.Equal(t, r1.CompareTo(r2), -1)
 }
+
+func TestReleaseCompareWith(t *testing.T) {
+	r1 := &Release{Name: "2024.10"}
+	r2 := &Release{Name: "2024.9.3"}
+
+	assert.Equal(t, 1, r1.CompareWith(r2, CalVer{}))
+	assert.Equal(t, -1, r1.CompareWith(r2, nil))
+}
@@ -0,0 +1,24 @@
+package provider
+
+import "strings"
+
+// PrefixRewriter returns a function that rewrites URLs according to a
+// prefix mapping table, e.g. "https://github.com/" to
+// "https://artifactory.corp.com/github/". When more than one prefix
+// matches, the longest one wins. URLs matching no prefix are kept as is.
+func PrefixRewriter(prefixes map[string]string) func(string) string {
+	return func(url string) string {
+		var from string
+		for prefix := range prefixes {
+			if strings.HasPrefix(url, prefix) && len(prefix) > len(from) {
+				from = prefix
+			}
+		}
+
+		if from == "" {
+			return url
+		}
+
+		return prefixes[from] + strings.TrimPrefix(url, from)
+	}
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixRewriter(t *testing.T) {
+	rewrite := PrefixRewriter(map[string]string{
+		"https://github.com/":                 "https://artifactory.corp.com/github/",
+		"https://github.com/santos/14-bis/":   "https://artifactory.corp.com/14-bis/",
+		"https://gitlab.com/api/v4/projects/": "https://artifactory.corp.com/gitlab/",
+	})
+
+	type testCase struct {
+		input    string
+		expected string
+	}
+	testCases := []testCase{
+		{
+			input:    "https://github.com/aureliano/caravela/releases/download/v0.1.0/checksums.txt",
+			expected: "https://artifactory.corp.com/github/aureliano/caravela/releases/download/v0.1.0/checksums.txt",
+		},
+		{
+			input:    "https://github.com/santos/14-bis/releases/download/v0.1.0/checksums.txt",
+			expected: "https://artifactory.corp.com/14-bis/releases/download/v0.1.0/checksums.txt",
+		},
+		{
+			input:    "https://example.com/checksums.txt",
+			expected: "https://example.com/checksums.txt",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, rewrite(tc.input))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+const rolloutBuckets = 100
+
+// RolloutPaused is the Rollout of a release offered to no installation, that is,
+// a rollout of 0%. Zero tells that the rollout is not set.
+const RolloutPaused = -1
+
+// Eligible tells whether the installation identified by installationID is
+// within the rollout of the release. The installation is hashed, along with
+// the release name, into one of a hundred buckets, so the same installations
+// stay eligible while the rollout percentage grows, but not always the same
+// ones are the first to receive a release. Every installation is eligible
+// when Rollout is not set, and none when it is RolloutPaused.
+func (r1 *Release) Eligible(installationID string) bool {
+	if !r1.Staged() {
+		return true
+	}
+
+	return rolloutBucket(installationID, r1.Name) < r1.Rollout
+}
+
+// Staged tells whether the release is offered to a part of the installations only.
+func (r1 *Release) Staged() bool {
+	return r1.Rollout != 0 && r1.Rollout < rolloutBuckets
+}
+
+func rolloutBucket(installationID, name string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(installationID))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(name))
+
+	return int(h.Sum32() % rolloutBuckets)
+}
+
+// parseRollout parses a rollout percentage, with or without the % sign. A rollout
+// of 0% is RolloutPaused.
+func parseRollout(value string) (int, bool) {
+	rollout, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%")))
+	if err != nil || rollout < 0 || rollout > rolloutBuckets {
+		return 0, false
+	} else if rollout == 0 {
+		return RolloutPaused, true
+	}
+
+	return rollout, true
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseEligible(t *testing.T) {
+	assert.True(t, (&Release{Name: "v1.6.0"}).Eligible("a"))
+	assert.True(t, (&Release{Name: "v1.6.0", Rollout: 100}).Eligible("a"))
+
+	release := &Release{Name: "v1.6.0", Rollout: 25}
+	eligible := 0
+	for i := 0; i < 1000; i++ {
+		if release.Eligible(fmt.Sprintf("installation-%d", i)) {
+			eligible++
+		}
+	}
+	assert.InDelta(t, 250, eligible, 50)
+}
+
+func TestReleaseEligiblePausedRollout(t *testing.T) {
+	release := &Release{Name: "v1.6.0", Description: "<!-- caravela: rollout: 0% -->"}
+	applyReleaseMetadata(release)
+
+	assert.True(t, release.Staged())
+	for i := 0; i < 1000; i++ {
+		assert.False(t, release.Eligible(fmt.Sprintf("installation-%d", i)))
+	}
+}
+
+func TestReleaseEligibleGrowingRollout(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("installation-%d", i)
+		if (&Release{Name: "v1.6.0", Rollout: 5}).Eligible(id) {
+			assert.True(t, (&Release{Name: "v1.6.0", Rollout: 25}).Eligible(id), id)
+		}
+	}
+}
+
+func TestReleaseStaged(t *testing.T) {
+	assert.False(t, (&Release{}).Staged())
+	assert.True(t, (&Release{Rollout: 1}).Staged())
+	assert.True(t, (&Release{Rollout: 99}).Staged())
+	assert.False(t, (&Release{Rollout: 100}).Staged())
+	assert.False(t, (&Release{}).Staged())
+	assert.True(t, (&Release{Rollout: RolloutPaused}).Staged())
+}
+
+func TestParseRollout(t *testing.T) {
+	for value, expected := range map[string]int{"5": 5, "25%": 25, " 100 % ": 100, "0": RolloutPaused, "0%": RolloutPaused} {
+		rollout, ok := parseRollout(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, rollout, value)
+	}
+
+	for _, value := range []string{"", "half", "-1", "101%"} {
+		_, ok := parseRollout(value)
+		assert.False(t, ok, value)
+	}
+}
@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VersionScheme tells how the versions of a project are parsed, compared and validated.
+type VersionScheme interface {
+	// Parse parses a version. The type of the value returned depends on the scheme.
+	Parse(version string) (interface{}, error)
+
+	// Compare compares version v1 with version v2. Versions that do not follow
+	// the scheme are compared lexically, so they must be left out beforehand,
+	// see ValidReleases.
+	// It returns 1, 0 or -1 if it is greater, equal or lesser.
+	Compare(v1, v2 string) int
+
+	// Validate returns an error if version does not follow the scheme.
+	Validate(version string) error
+
+	// Prerelease returns the dot separated pre-release identifiers of version, e.g.
+	// rc.1 for 2.0.0-rc.1. It is empty for final versions and for versions that do
+	// not follow the scheme. Channels rely on it to tell pre-releases apart.
+	Prerelease(version string) string
+}
+
+// SemVer is the Semantic Versioning 2.0.0 scheme, e.g. v1.4.2 or 2.0.0-rc.1.
+// It is the scheme used when none is set. Parse returns a Version.
+type SemVer struct{}
+
+// CalVer is a calendar versioning scheme, e.g. 2024.06.1 or 24.6: two or more dot
+// separated numbers, with an optional modifier such as -beta. Numbers are compared
+// from left to right, a missing one counting as zero, and a version with a
+// modifier has lower precedence than the same version without it.
+// Parse returns a CalVersion.
+type CalVer struct{}
+
+// Monotonic is a scheme of increasing build numbers, e.g. 123 or r123. The number
+// may be preceded by a prefix made of letters. Parse returns an uint64.
+type Monotonic struct{}
+
+// CalVersion is a calendar version as parsed by CalVer.
+type CalVersion struct {
+	Segments []uint64
+	Modifier string
+}
+
+var monotonicRegex = regexp.MustCompile(`^[a-zA-Z]*[-_.]?(\d+)$`)
+
+func (SemVer) Parse(version string) (interface{}, error) {
+	return ParseVersion(version)
+}
+
+func (SemVer) Compare(v1, v2 string) int {
+	return compareVersions(v1, v2)
+}
+
+func (SemVer) Validate(version string) error {
+	_, err := ParseVersion(version)
+	return err
+}
+
+func (SemVer) Prerelease(version string) string {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return ""
+	}
+
+	return strings.Join(v.Prerelease, ".")
+}
+
+func (CalVer) Parse(version string) (interface{}, error) {
+	return parseCalVersion(version)
+}
+
+func (CalVer) Compare(v1, v2 string) int {
+	ver1, err1 := parseCalVersion(v1)
+	ver2, err2 := parseCalVersion(v2)
+	if err1 != nil || err2 != nil {
+		return strings.Compare(v1, v2)
+	}
+
+	size := len(ver1.Segments)
+	if len(ver2.Segments) > size {
+		size = len(ver2.Segments)
+	}
+
+	for i := 0; i < size; i++ {
+		var s1, s2 uint64
+		if i < len(ver1.Segments) {
+			s1 = ver1.Segments[i]
+		}
+		if i < len(ver2.Segments) {
+			s2 = ver2.Segments[i]
+		}
+
+		switch {
+		case s1 > s2:
+			return 1
+		case s1 < s2:
+			return -1
+		}
+	}
+
+	var mod1, mod2 []string
+	if ver1.Modifier != "" {
+		mod1 = strings.Split(ver1.Modifier, ".")
+	}
+	if ver2.Modifier != "" {
+		mod2 = strings.Split(ver2.Modifier, ".")
+	}
+
+	return comparePrerelease(mod1, mod2)
+}
+
+func (CalVer) Validate(version string) error {
+	_, err := parseCalVersion(version)
+	return err
+}
+
+func (CalVer) Prerelease(version string) string {
+	v, err := parseCalVersion(version)
+	if err != nil {
+		return ""
+	}
+
+	return v.Modifier
+}
+
+func (Monotonic) Parse(version string) (interface{}, error) {
+	return parseMonotonicVersion(version)
+}
+
+func (Monotonic) Compare(v1, v2 string) int {
+	ver1, err1 := parseMonotonicVersion(v1)
+	ver2, err2 := parseMonotonicVersion(v2)
+
+	switch {
+	case err1 != nil || err2 != nil:
+		return strings.Compare(v1, v2)
+	case ver1 > ver2:
+		return 1
+	case ver1 < ver2:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func (Monotonic) Validate(version string) error {
+	_, err := parseMonotonicVersion(version)
+	return err
+}
+
+// Prerelease is always empty, build numbers have no pre-releases.
+func (Monotonic) Prerelease(string) string {
+	return ""
+}
+
+func parseCalVersion(version string) (CalVersion, error) {
+	var v CalVersion
+	s := strings.TrimPrefix(version, "v")
+
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.Modifier = s[i+1:]
+		s = s[:i]
+
+		if _, err := parseVersionIdentifiers(v.Modifier, false); err != nil {
+			return CalVersion{}, fmt.Errorf("invalid calendar version %q: modifier %s", version, err)
+		}
+	}
+
+	segments := strings.Split(s, ".")
+	if len(segments) < 2 {
+		return CalVersion{}, fmt.Errorf("invalid calendar version %q: expected at least year and month", version)
+	}
+
+	v.Segments = make([]uint64, len(segments))
+	for i, segment := range segments {
+		if !isNumericIdentifier(segment) {
+			return CalVersion{}, fmt.Errorf("invalid calendar version %q: %q is not a number", version, segment)
+		}
+
+		n, err := strconv.ParseUint(segment, 10, 64)
+		if err != nil {
+			return CalVersion{}, fmt.Errorf("invalid calendar version %q: %w", version, err)
+		}
+		v.Segments[i] = n
+	}
+
+	return v, nil
+}
+
+func parseMonotonicVersion(version string) (uint64, error) {
+	match := monotonicRegex.FindStringSubmatch(version)
+	if match == nil {
+		return 0, fmt.Errorf("invalid monotonic version %q: expected a build number", version)
+	}
+
+	n, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid monotonic version %q: %w", version, err)
+	}
+
+	return n, nil
+}
+
+// LatestRelease returns the greatest release according to scheme, or nil if
+// releases is empty. Releases whose names don't follow scheme, e.g. nightly,
+// are left out. SemVer is used when scheme is nil.
+func LatestRelease(releases []*Release, scheme VersionScheme) *Release {
+	var lastRelease *Release
+	for _, release := range releases {
+		if !followsScheme(release, scheme) {
+			continue
+		} else if lastRelease == nil {
+			lastRelease = release
+		} else if lastRelease.CompareWith(release, scheme) == -1 {
+			lastRelease = release
+		}
+	}
+
+	return lastRelease
+}
+
+// SortReleases sorts releases according to scheme, newest first. Releases are
+// expected to follow scheme, see ValidReleases. SemVer is used when scheme is nil.
+func SortReleases(releases []*Release, scheme VersionScheme) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].CompareWith(releases[j], scheme) == 1
+	})
+}
+
+// ValidReleases returns the releases whose names follow scheme, leaving out the
+// ones such as nightly that can't be compared. SemVer is used when scheme is nil.
+func ValidReleases(releases []*Release, scheme VersionScheme) []*Release {
+	valid := make([]*Release, 0, len(releases))
+	for _, release := range releases {
+		if followsScheme(release, scheme) {
+			valid = append(valid, release)
+		}
+	}
+
+	return valid
+}
+
+func followsScheme(release *Release, scheme VersionScheme) bool {
+	return schemeOrDefault(scheme).Validate(release.Name) == nil
+}
+
+func schemeOrDefault(scheme VersionScheme) VersionScheme {
+	if scheme == nil {
+		return SemVer{}
+	}
+
+	return scheme
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemVerScheme(t *testing.T) {
+	s := SemVer{}
+
+	v, err := s.Parse("v1.0.0-rc.1")
+	assert.Nil(t, err)
+	assert.Equal(t, Version{Major: 1, Prerelease: []string{"rc", "1"}}, v)
+
+	assert.Equal(t, 1, s.Compare("1.0.0-rc.10", "1.0.0-rc.2"))
+	assert.Nil(t, s.Validate("1.0.0"))
+	assert.EqualError(t, s.Validate("2024.06"), `invalid semantic version "2024.06": expected major.minor.patch`)
+
+	assert.Equal(t, "rc.1", s.Prerelease("v1.0.0-rc.1"))
+	assert.Empty(t, s.Prerelease("1.0.0"))
+	assert.Empty(t, s.Prerelease("nightly"))
+}
+
+func TestCalVerParse(t *testing.T) {
+	v, err := CalVer{}.Parse("2024.06.1")
+	assert.Nil(t, err)
+	assert.Equal(t, CalVersion{Segments: []uint64{2024, 6, 1}}, v)
+
+	v, err = CalVer{}.Parse("v24.6-beta.2")
+	assert.Nil(t, err)
+	assert.Equal(t, CalVersion{Segments: []uint64{24, 6}, Modifier: "beta.2"}, v)
+}
+
+func TestCalVerValidate(t *testing.T) {
+	assert.Nil(t, CalVer{}.Validate("24.6"))
+	assert.EqualError(t, CalVer{}.Validate("2024"),
+		`invalid calendar version "2024": expected at least year and month`)
+	assert.EqualError(t, CalVer{}.Validate("2024.jun"), `invalid calendar version "2024.jun": "jun" is not a number`)
+	assert.EqualError(t, CalVer{}.Validate("2024.06-"),
+		`invalid calendar version "2024.06-": modifier has an empty identifier`)
+}
+
+func TestCalVerPrerelease(t *testing.T) {
+	assert.Equal(t, "beta.2", CalVer{}.Prerelease("v24.6-beta.2"))
+	assert.Empty(t, CalVer{}.Prerelease("2024.06.1"))
+	assert.Empty(t, CalVer{}.Prerelease("2024"))
+}
+
+func TestCalVerCompare(t *testing.T) {
+	ordered := []string{"2023.12.5", "2024.06-beta", "2024.06-beta.2", "2024.06", "2024.06.1", "2024.6.2", "2024.10"}
+
+	for i := 1; i < len(ordered); i++ {
+		assert.Equal(t, -1, CalVer{}.Compare(ordered[i-1], ordered[i]), ordered[i-1]+" < "+ordered[i])
+		assert.Equal(t, 1, CalVer{}.Compare(ordered[i], ordered[i-1]), ordered[i]+" > "+ordered[i-1])
+	}
+
+	assert.Equal(t, 0, CalVer{}.Compare("24.6", "24.06.0"))
+	assert.Equal(t, 1, CalVer{}.Compare("nightly", "24.6"))
+}
+
+func TestMonotonicParse(t *testing.T) {
+	v, err := Monotonic{}.Parse("r123")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(123), v)
+
+	v, err = Monotonic{}.Parse("build-45")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(45), v)
+}
+
+func TestMonotonicValidate(t *testing.T) {
+	assert.Nil(t, Monotonic{}.Validate("123"))
+	assert.EqualError(t, Monotonic{}.Validate("1.2.3"), `invalid monotonic version "1.2.3": expected a build number`)
+	assert.EqualError(t, Monotonic{}.Validate("r"), `invalid monotonic version "r": expected a build number`)
+}
+
+func TestMonotonicPrerelease(t *testing.T) {
+	assert.Empty(t, Monotonic{}.Prerelease("r123"))
+}
+
+func TestMonotonicCompare(t *testing.T) {
+	assert.Equal(t, 1, Monotonic{}.Compare("r123", "r99"))
+	assert.Equal(t, -1, Monotonic{}.Compare("r99", "r123"))
+	assert.Equal(t, 0, Monotonic{}.Compare("r123", "123"))
+	assert.Equal(t, -1, Monotonic{}.Compare("nightly", "r1"))
+}
+
+func TestLatestRelease(t *testing.T) {
+	releases := []*Release{{Name: "r99"}, {Name: "r123"}, {Name: "r100"}}
+
+	assert.Equal(t, "r123", LatestRelease(releases, Monotonic{}).Name)
+	assert.Nil(t, LatestRelease(releases, nil))
+	assert.Nil(t, LatestRelease(nil, Monotonic{}))
+
+	releases = []*Release{{Name: "1.2.0"}, {Name: "nightly"}, {Name: "1.10.0"}}
+	assert.Equal(t, "1.10.0", LatestRelease(releases, nil).Name)
+}
+
+func TestValidReleases(t *testing.T) {
+	releases := []*Release{{Name: "1.2.0"}, {Name: "nightly"}, {Name: "r10"}}
+
+	assert.Equal(t, []*Release{{Name: "1.2.0"}}, ValidReleases(releases, nil))
+	assert.Equal(t, []*Release{{Name: "r10"}}, ValidReleases(releases, Monotonic{}))
+	assert.Empty(t, ValidReleases(nil, nil))
+}
+
+func TestSortReleases(t *testing.T) {
+	releases := []*Release{{Name: "v0.1.2"}, {Name: "v0.1.10"}, {Name: "v0.1.9"}}
+	SortReleases(releases, nil)
+	assert.Equal(t, []*Release{{Name: "v0.1.10"}, {Name: "v0.1.9"}, {Name: "v0.1.2"}}, releases)
+
+	releases = []*Release{{Name: "r9"}, {Name: "r10"}}
+	SortReleases(releases, Monotonic{})
+	assert.Equal(t, []*Release{{Name: "r10"}, {Name: "r9"}}, releases)
+}
+
+func TestSchemeOrDefault(t *testing.T) {
+	assert.Equal(t, SemVer{}, schemeOrDefault(nil))
+	assert.Equal(t, CalVer{}, schemeOrDefault(CalVer{}))
+}
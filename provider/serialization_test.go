@@ -16,10 +16,7 @@ func TestSerializeRelease(t *testing.T) {
 		Name:        "v0.1.0-dev",
 		Description: "Development version.",
 		ReleasedAt:  time.Date(2023, 3, 6, 9, 59, 26, 0, time.UTC),
-		Assets: []struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{
+		Assets: []Asset{
 			{Name: "f1", URL: "u1"},
 			{Name: "f2", URL: "u2"},
 			{Name: "f3", URL: "u3"},
@@ -49,10 +46,7 @@ func TestDeserializeRelease(t *testing.T) {
 		Name:        "v0.1.0-dev",
 		Description: "Development version.",
 		ReleasedAt:  time.Date(2023, 3, 6, 9, 59, 26, 0, time.UTC),
-		Assets: []struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{
+		Assets: []Asset{
 			{Name: "f1", URL: "u1"},
 			{Name: "f2", URL: "u2"},
 			{Name: "f3", URL: "u3"},
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TagFilter selects the releases of a project by their tags, e.g. in a monorepo
+// whose releases are tagged as cli/v1.4.0 and agent/v2.0.1.
+//
+// Prefix, when set, is stripped from the tags starting with it, and the other tags
+// are ignored. Pattern, when set, must match the tag (after the prefix is stripped)
+// and its first capture group, if any, is taken as the version. The zero value
+// selects every release.
+type TagFilter struct {
+	Prefix  string
+	Pattern *regexp.Regexp
+}
+
+// Match tells whether tag is selected by the filter and returns the version it holds.
+func (f TagFilter) Match(tag string) (string, bool) {
+	version := tag
+	if f.Prefix != "" {
+		if !strings.HasPrefix(tag, f.Prefix) {
+			return "", false
+		}
+		version = strings.TrimPrefix(tag, f.Prefix)
+	}
+
+	if f.Pattern != nil {
+		match := f.Pattern.FindStringSubmatch(version)
+		if match == nil {
+			return "", false
+		} else if len(match) > 1 {
+			version = match[1]
+		}
+	}
+
+	return version, version != ""
+}
+
+func (f TagFilter) isZero() bool {
+	return f.Prefix == "" && f.Pattern == nil
+}
+
+// filterReleases keeps the releases whose tags are selected by filter, naming them
+// after the version found in the tag. The original tag is kept in Release.Tag.
+func filterReleases(releases []*Release, filter TagFilter) []*Release {
+	if filter.isZero() {
+		return releases
+	}
+
+	filtered := make([]*Release, 0, len(releases))
+	for _, release := range releases {
+		version, ok := filter.Match(release.Name)
+		if !ok {
+			continue
+		}
+
+		release.Tag = release.Name
+		release.Name = version
+		filtered = append(filtered, release)
+	}
+
+	return filtered
+}
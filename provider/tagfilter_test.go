@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagFilterMatch(t *testing.T) {
+	type testCase struct {
+		name     string
+		filter   TagFilter
+		tag      string
+		expected string
+		match    bool
+	}
+	testCases := []testCase{
+		{
+			name:     "zero value matches any tag",
+			tag:      "cli/v1.4.0",
+			expected: "cli/v1.4.0",
+			match:    true,
+		},
+		{
+			name:     "prefix is stripped",
+			filter:   TagFilter{Prefix: "cli/"},
+			tag:      "cli/v1.4.0",
+			expected: "v1.4.0",
+			match:    true,
+		},
+		{
+			name:   "prefix does not match",
+			filter: TagFilter{Prefix: "cli/"},
+			tag:    "agent/v2.0.1",
+		},
+		{
+			name:   "tag is the prefix only",
+			filter: TagFilter{Prefix: "cli/"},
+			tag:    "cli/",
+		},
+		{
+			name:     "pattern capture group is the version",
+			filter:   TagFilter{Pattern: regexp.MustCompile(`^cli-(\d+\.\d+\.\d+)$`)},
+			tag:      "cli-1.4.0",
+			expected: "1.4.0",
+			match:    true,
+		},
+		{
+			name:     "pattern without capture group",
+			filter:   TagFilter{Pattern: regexp.MustCompile(`^v\d+\.\d+\.\d+$`)},
+			tag:      "v1.4.0",
+			expected: "v1.4.0",
+			match:    true,
+		},
+		{
+			name:   "pattern does not match",
+			filter: TagFilter{Pattern: regexp.MustCompile(`^cli-(\d+\.\d+\.\d+)$`)},
+			tag:    "agent-2.0.1",
+		},
+		{
+			name:     "pattern applied after prefix",
+			filter:   TagFilter{Prefix: "cli/", Pattern: regexp.MustCompile(`^v(.+)$`)},
+			tag:      "cli/v1.4.0",
+			expected: "1.4.0",
+			match:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			version, match := tc.filter.Match(tc.tag)
+			assert.Equal(t, tc.expected, version)
+			assert.Equal(t, tc.match, match)
+		})
+	}
+}
+
+func TestFilterReleases(t *testing.T) {
+	releases := []*Release{{Name: "cli/v1.4.0"}, {Name: "agent/v2.0.1"}, {Name: "cli/v1.3.9"}}
+
+	actual := filterReleases(releases, TagFilter{Prefix: "cli/"})
+	assert.Equal(t, []*Release{{Name: "v1.4.0", Tag: "cli/v1.4.0"}, {Name: "v1.3.9", Tag: "cli/v1.3.9"}}, actual)
+}
+
+func TestFilterReleasesZeroFilter(t *testing.T) {
+	releases := []*Release{{Name: "cli/v1.4.0"}, {Name: "agent/v2.0.1"}}
+
+	actual := filterReleases(releases, TagFilter{})
+	assert.Equal(t, []*Release{{Name: "cli/v1.4.0"}, {Name: "agent/v2.0.1"}}, actual)
+}
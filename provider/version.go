@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version as specified by Semantic Versioning 2.0.0
+// (https://semver.org/spec/v2.0.0.html).
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string
+	Build      []string
+}
+
+// ParseVersion parses a semantic version. A leading "v", as usually found
+// in tags, is accepted. It returns an error describing why the version is
+// not valid, if so.
+func ParseVersion(version string) (Version, error) {
+	var v Version
+	s := strings.TrimPrefix(version, "v")
+
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		build := s[i+1:]
+		s = s[:i]
+
+		ids, err := parseVersionIdentifiers(build, false)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid semantic version %q: build metadata %s", version, err)
+		}
+		v.Build = ids
+	}
+
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		prerelease := s[i+1:]
+		s = s[:i]
+
+		ids, err := parseVersionIdentifiers(prerelease, true)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid semantic version %q: pre-release %s", version, err)
+		}
+		v.Prerelease = ids
+	}
+
+	core := strings.Split(s, ".")
+	const coreSize = 3
+	if len(core) != coreSize {
+		return Version{}, fmt.Errorf("invalid semantic version %q: expected major.minor.patch", version)
+	}
+
+	numbers := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range core {
+		if !isNumericIdentifier(part) {
+			return Version{}, fmt.Errorf("invalid semantic version %q: %q is not a number", version, part)
+		} else if len(part) > 1 && part[0] == '0' {
+			return Version{}, fmt.Errorf("invalid semantic version %q: %q has leading zeros", version, part)
+		}
+
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid semantic version %q: %w", version, err)
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// CompareVersions compares two semantic versions following the precedence rules of
+// Semantic Versioning 2.0.0. It returns 1, 0 or -1 if v1 is greater, equal or lesser
+// than v2, or an error if any of them is not a semantic version.
+func CompareVersions(v1, v2 string) (int, error) {
+	ver1, err := ParseVersion(v1)
+	if err != nil {
+		return 0, err
+	}
+
+	ver2, err := ParseVersion(v2)
+	if err != nil {
+		return 0, err
+	}
+
+	return ver1.Compare(ver2), nil
+}
+
+// Compare compares version v1 with version v2. Build metadata is ignored.
+// It returns 1, 0 or -1 if it is greater, equal or lesser.
+func (v1 Version) Compare(v2 Version) int {
+	for _, parts := range [][]uint64{{v1.Major, v2.Major}, {v1.Minor, v2.Minor}, {v1.Patch, v2.Patch}} {
+		switch {
+		case parts[0] > parts[1]:
+			return 1
+		case parts[0] < parts[1]:
+			return -1
+		}
+	}
+
+	return comparePrerelease(v1.Prerelease, v2.Prerelease)
+}
+
+// IsPrerelease tells whether the version has pre-release identifiers.
+func (v1 Version) IsPrerelease() bool {
+	return len(v1.Prerelease) > 0
+}
+
+func (v1 Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v1.Major, v1.Minor, v1.Patch)
+
+	if len(v1.Prerelease) > 0 {
+		s += "-" + strings.Join(v1.Prerelease, ".")
+	}
+
+	if len(v1.Build) > 0 {
+		s += "+" + strings.Join(v1.Build, ".")
+	}
+
+	return s
+}
+
+// comparePrerelease compares two lists of pre-release identifiers. A version without
+// pre-release has higher precedence and, when all identifiers are equal, the larger
+// list has the higher precedence.
+func comparePrerelease(pr1, pr2 []string) int {
+	switch {
+	case len(pr1) == 0 && len(pr2) == 0:
+		return 0
+	case len(pr1) == 0:
+		return 1
+	case len(pr2) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(pr1) && i < len(pr2); i++ {
+		if c := compareVersionIdentifiers(pr1[i], pr2[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(pr1) > len(pr2):
+		return 1
+	case len(pr1) < len(pr2):
+		return -1
+	default:
+		return 0
+	}
+}
+
+// compareVersionIdentifiers compares two pre-release identifiers. Numeric identifiers
+// are compared numerically and always have lower precedence than alphanumeric ones,
+// which are compared lexically in ASCII sort order.
+func compareVersionIdentifiers(id1, id2 string) int {
+	num1, num2 := isNumericIdentifier(id1), isNumericIdentifier(id2)
+
+	switch {
+	case num1 && num2:
+		return compareVersionParts(id1, id2)
+	case num1:
+		return -1
+	case num2:
+		return 1
+	default:
+		return strings.Compare(id1, id2)
+	}
+}
+
+func parseVersionIdentifiers(s string, prerelease bool) ([]string, error) {
+	ids := strings.Split(s, ".")
+
+	for _, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("has an empty identifier")
+		}
+
+		for _, c := range id {
+			if !isVersionIdentifierChar(c) {
+				return nil, fmt.Errorf("identifier %q has invalid character %q", id, c)
+			}
+		}
+
+		if prerelease && len(id) > 1 && id[0] == '0' && isNumericIdentifier(id) {
+			return nil, fmt.Errorf("identifier %q has leading zeros", id)
+		}
+	}
+
+	return ids, nil
+}
+
+func isVersionIdentifierChar(c rune) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '-'
+}
+
+func isNumericIdentifier(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
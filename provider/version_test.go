@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("v1.20.300-rc.1+build.5")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), v.Major)
+	assert.Equal(t, uint64(20), v.Minor)
+	assert.Equal(t, uint64(300), v.Patch)
+	assert.Equal(t, []string{"rc", "1"}, v.Prerelease)
+	assert.Equal(t, []string{"build", "5"}, v.Build)
+	assert.True(t, v.IsPrerelease())
+	assert.Equal(t, "1.20.300-rc.1+build.5", v.String())
+}
+
+func TestParseVersionWithoutPrefix(t *testing.T) {
+	v, err := ParseVersion("0.1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, Version{Minor: 1}, v)
+	assert.False(t, v.IsPrerelease())
+	assert.Equal(t, "0.1.0", v.String())
+}
+
+func TestParseVersionBuildWithHyphen(t *testing.T) {
+	v, err := ParseVersion("1.0.0+exp.sha-5114f85")
+	assert.Nil(t, err)
+	assert.Nil(t, v.Prerelease)
+	assert.Equal(t, []string{"exp", "sha-5114f85"}, v.Build)
+}
+
+func TestParseVersionBuildLeadingZeros(t *testing.T) {
+	v, err := ParseVersion("1.0.0+001")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"001"}, v.Build)
+}
+
+func TestParseVersionErrors(t *testing.T) {
+	testCases := map[string]string{
+		"v1.0":        `invalid semantic version "v1.0": expected major.minor.patch`,
+		"1.0.0.0":     `invalid semantic version "1.0.0.0": expected major.minor.patch`,
+		"1.x.0":       `invalid semantic version "1.x.0": "x" is not a number`,
+		"01.0.0":      `invalid semantic version "01.0.0": "01" has leading zeros`,
+		"1.0.0-":      `invalid semantic version "1.0.0-": pre-release has an empty identifier`,
+		"1.0.0-rc..1": `invalid semantic version "1.0.0-rc..1": pre-release has an empty identifier`,
+		"1.0.0-rc.01": `invalid semantic version "1.0.0-rc.01": pre-release identifier "01" has leading zeros`,
+		"1.0.0-rc_1":  `invalid semantic version "1.0.0-rc_1": pre-release identifier "rc_1" has invalid character '_'`,
+		"1.0.0+":      `invalid semantic version "1.0.0+": build metadata has an empty identifier`,
+		"1.0.0+build+1": `invalid semantic version "1.0.0+build+1": build metadata identifier "build+1" ` +
+			`has invalid character '+'`,
+		"":       `invalid semantic version "": expected major.minor.patch`,
+		"latest": `invalid semantic version "latest": expected major.minor.patch`,
+		"1.0.99999999999999999999": `invalid semantic version "1.0.99999999999999999999": strconv.ParseUint: ` +
+			`parsing "99999999999999999999": value out of range`,
+	}
+
+	for input, expected := range testCases {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseVersion(input)
+			assert.EqualError(t, err, expected)
+		})
+	}
+}
+
+func TestVersionComparePrecedence(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0-rc.2",
+		"1.0.0-rc.10",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+		"10.0.0",
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		lower, greater := ordered[i-1], ordered[i]
+		t.Run(lower+" < "+greater, func(t *testing.T) {
+			c, err := CompareVersions(lower, greater)
+			assert.Nil(t, err)
+			assert.Equal(t, -1, c)
+
+			c, err = CompareVersions(greater, lower)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, c)
+		})
+	}
+}
+
+func TestVersionCompareIgnoresBuild(t *testing.T) {
+	c, err := CompareVersions("v1.0.0+build.5", "1.0.0+build.6")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, c)
+
+	c, err = CompareVersions("1.0.0-rc.1+build.5", "1.0.0-rc.1")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, c)
+}
+
+func TestVersionCompareLargeIdentifiers(t *testing.T) {
+	c, err := CompareVersions("1.0.0-99999999999999999999", "1.0.0-100000000000000000000")
+	assert.Nil(t, err)
+	assert.Equal(t, -1, c)
+}
+
+func TestCompareVersionsError(t *testing.T) {
+	_, err := CompareVersions("1.0", "1.0.0")
+	assert.EqualError(t, err, `invalid semantic version "1.0": expected major.minor.patch`)
+
+	_, err = CompareVersions("1.0.0", "1.0.0-")
+	assert.EqualError(t, err, `invalid semantic version "1.0.0-": pre-release has an empty identifier`)
+}
+
+func TestReleaseVersion(t *testing.T) {
+	r := &Release{Name: "v0.2.0-beta.3"}
+	v, err := r.Version()
+	assert.Nil(t, err)
+	assert.Equal(t, "0.2.0-beta.3", v.String())
+
+	r = &Release{Name: "nightly"}
+	_, err = r.Version()
+	assert.NotNil(t, err)
+}
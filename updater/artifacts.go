@@ -0,0 +1,125 @@
+package updater
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/aureliano/caravela/provider"
+)
+
+const artifactsFileName = "artifacts.json"
+const archiveArtifactType = "Archive"
+const binaryArtifactType = "Uploadable Binary"
+
+// goreleaserArtifact is a representation - in JSON form - of an entry of
+// the artifacts.json file written by goreleaser.
+type goreleaserArtifact struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Goos   string `json:"goos"`
+	Goarch string `json:"goarch"`
+	Goarm  string `json:"goarm"`
+	Type   string `json:"type"`
+	Extra  struct {
+		Checksum string `json:"Checksum"`
+	} `json:"extra"`
+}
+
+type platform struct {
+	goos   string
+	goarch string
+	goarm  string
+}
+
+var mpCurrentPlatform = currentPlatform
+
+// findReleaseFileFromArtifacts downloads the artifacts.json of a release into
+// dir and picks the archive built for the running platform. The checksum
+// recorded by goreleaser, if any, is returned as the asset digest.
+func findReleaseFileFromArtifacts(
+	client provider.HTTPClientPlugin,
+	release *provider.Release,
+	furl, dir string,
+) (provider.Asset, error) {
+	fileArtifacts := filepath.Join(dir, artifactsFileName)
+	_, err := mpDownloadFile(client, furl, fileArtifacts, "")
+	if err != nil {
+		return provider.Asset{}, err
+	}
+
+	artifacts, err := readArtifacts(fileArtifacts)
+	if err != nil {
+		return provider.Asset{}, err
+	}
+
+	artifact := findPlatformArtifact(mpCurrentPlatform(), artifacts)
+	if artifact == nil {
+		return provider.Asset{}, nil
+	}
+
+	for _, asset := range release.Assets {
+		if asset.Name == artifact.Name {
+			if asset.Digest == "" {
+				asset.Digest = artifact.Extra.Checksum
+			}
+			return asset, nil
+		}
+	}
+
+	return provider.Asset{}, nil
+}
+
+func readArtifacts(path string) ([]goreleaserArtifact, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []goreleaserArtifact
+	err = json.Unmarshal(bytes, &artifacts)
+
+	return artifacts, err
+}
+
+// findPlatformArtifact picks the archive built for p or, if there is none, the
+// raw binary uploaded as is (goreleaser format: binary).
+func findPlatformArtifact(p platform, artifacts []goreleaserArtifact) *goreleaserArtifact {
+	if artifact := findPlatformArtifactOfType(p, artifacts, archiveArtifactType); artifact != nil {
+		return artifact
+	}
+
+	return findPlatformArtifactOfType(p, artifacts, binaryArtifactType)
+}
+
+func findPlatformArtifactOfType(p platform, artifacts []goreleaserArtifact, tp string) *goreleaserArtifact {
+	for i, artifact := range artifacts {
+		if artifact.Type != tp || artifact.Goos != p.goos || artifact.Goarch != p.goarch {
+			continue
+		}
+
+		if artifact.Goarm != "" && p.goarm != "" && artifact.Goarm != p.goarm {
+			continue
+		}
+
+		return &artifacts[i]
+	}
+
+	return nil
+}
+
+func currentPlatform() platform {
+	p := platform{goos: runtime.GOOS, goarch: runtime.GOARCH}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "GOARM" {
+				p.goarm = setting.Value
+			}
+		}
+	}
+
+	return p
+}
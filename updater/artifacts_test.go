@@ -0,0 +1,175 @@
+package updater
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aureliano/caravela/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+const artifactsJSON = `[
+	{"name":"14-bis_Linux_x86_64.tar.gz","path":"dist/14-bis_Linux_x86_64.tar.gz",
+		"goos":"linux","goarch":"amd64","type":"Archive","extra":{"Checksum":"sha256:123"}},
+	{"name":"14-bis_Linux_armv6.tar.gz","path":"dist/14-bis_Linux_armv6.tar.gz",
+		"goos":"linux","goarch":"arm","goarm":"6","type":"Archive","extra":{"Checksum":"sha256:456"}},
+	{"name":"14-bis_Linux_armv7.tar.gz","path":"dist/14-bis_Linux_armv7.tar.gz",
+		"goos":"linux","goarch":"arm","goarm":"7","type":"Archive"},
+	{"name":"14-bis","path":"dist/14-bis_linux_amd64_v1/14-bis",
+		"goos":"linux","goarch":"amd64","type":"Binary"},
+	{"name":"checksums.txt","path":"dist/checksums.txt","type":"Checksum"}
+]`
+
+func artifactsRelease() *provider.Release {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis_Linux_armv6.tar.gz", URL: "http://file-linux-armv6.tar.gz"},
+		{Name: "14-bis_Linux_armv7.tar.gz", URL: "http://file-linux-armv7.tar.gz"},
+		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux-amd64.tar.gz"},
+		{Name: "artifacts.json", URL: "http://artifacts.json"},
+		{Name: "checksums.txt", URL: "http://checksums.txt"},
+	}
+
+	return release
+}
+
+func mockArtifactsDownload(content string) {
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		if sourceURL != "http://artifacts.json" {
+			return "", fmt.Errorf("unexpected download of %s", sourceURL)
+		}
+		return "", os.WriteFile(dest, []byte(content), 0600)
+	}
+}
+
+func TestFindReleaseFileFromArtifacts(t *testing.T) {
+	defer func() {
+		mpDownloadFile = downloadFile
+		mpCurrentPlatform = currentPlatform
+	}()
+
+	mockArtifactsDownload(artifactsJSON)
+	dir, _ := os.MkdirTemp("", "test-artifacts-*")
+	defer os.RemoveAll(dir)
+
+	type testCase struct {
+		name     string
+		input    platform
+		expected provider.Asset
+	}
+	testCases := []testCase{
+		{
+			name:  "linux amd64",
+			input: platform{goos: "linux", goarch: "amd64"},
+			expected: provider.Asset{
+				Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux-amd64.tar.gz", Digest: "sha256:123",
+			},
+		},
+		{
+			name:     "linux armv7",
+			input:    platform{goos: "linux", goarch: "arm", goarm: "7"},
+			expected: provider.Asset{Name: "14-bis_Linux_armv7.tar.gz", URL: "http://file-linux-armv7.tar.gz"},
+		},
+		{
+			name:  "linux arm unknown version",
+			input: platform{goos: "linux", goarch: "arm"},
+			expected: provider.Asset{
+				Name: "14-bis_Linux_armv6.tar.gz", URL: "http://file-linux-armv6.tar.gz", Digest: "sha256:456",
+			},
+		},
+		{
+			name:     "unknown platform",
+			input:    platform{goos: "plan9", goarch: "amd64"},
+			expected: provider.Asset{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mpCurrentPlatform = func() platform { return tc.input }
+			actual, err := findReleaseFileFromArtifacts(nil, artifactsRelease(), "http://artifacts.json", dir)
+
+			assert.Nil(t, err, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestFindReleaseFileFromArtifactsRawBinary(t *testing.T) {
+	defer func() {
+		mpDownloadFile = downloadFile
+		mpCurrentPlatform = currentPlatform
+	}()
+
+	mockArtifactsDownload(`[
+		{"name":"14-bis_linux_amd64","path":"dist/14-bis_linux_amd64","goos":"linux","goarch":"amd64",
+			"type":"Uploadable Binary","extra":{"Checksum":"sha256:789"}},
+		{"name":"14-bis_darwin_amd64.tar.gz","path":"dist/14-bis_darwin_amd64.tar.gz","goos":"darwin",
+			"goarch":"amd64","type":"Archive"},
+		{"name":"14-bis_darwin_amd64","path":"dist/14-bis_darwin_amd64","goos":"darwin","goarch":"amd64",
+			"type":"Uploadable Binary"}
+	]`)
+	dir := t.TempDir()
+
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis_linux_amd64", URL: "http://file-linux"},
+		{Name: "14-bis_darwin_amd64.tar.gz", URL: "http://file-darwin.tar.gz"},
+		{Name: "14-bis_darwin_amd64", URL: "http://file-darwin"},
+	}
+
+	mpCurrentPlatform = func() platform { return platform{goos: "linux", goarch: "amd64"} }
+	actual, err := findReleaseFileFromArtifacts(nil, release, "http://artifacts.json", dir)
+	assert.Nil(t, err, err)
+	assert.Equal(t, provider.Asset{Name: "14-bis_linux_amd64", URL: "http://file-linux", Digest: "sha256:789"}, actual)
+
+	mpCurrentPlatform = func() platform { return platform{goos: "darwin", goarch: "amd64"} }
+	actual, err = findReleaseFileFromArtifacts(nil, release, "http://artifacts.json", dir)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "14-bis_darwin_amd64.tar.gz", actual.Name)
+}
+
+func TestFindReleaseFileFromArtifactsBrokenJson(t *testing.T) {
+	defer func() { mpDownloadFile = downloadFile }()
+
+	mockArtifactsDownload(`[{]`)
+	dir, _ := os.MkdirTemp("", "test-artifacts-*")
+	defer os.RemoveAll(dir)
+
+	_, err := findReleaseFileFromArtifacts(nil, artifactsRelease(), "http://artifacts.json", dir)
+	assert.NotNil(t, err)
+}
+
+func TestDownloadToWithArtifacts(t *testing.T) {
+	defer func() {
+		mpDownloadFile = downloadFile
+		mpCurrentPlatform = currentPlatform
+	}()
+
+	var downloads []string
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		downloads = append(downloads, sourceURL)
+		if sourceURL == "http://artifacts.json" {
+			return "", os.WriteFile(dest, []byte(artifactsJSON), 0600)
+		}
+		return "", nil
+	}
+	mpCurrentPlatform = func() platform { return platform{goos: "linux", goarch: "amd64"} }
+
+	dir, _ := os.MkdirTemp("", "test-artifacts-*")
+	defer os.RemoveAll(dir)
+
+	download, err := downloadTo(nil, artifactsRelease(), dir, nil, Options{})
+	assert.Nil(t, err, err)
+	assert.Equal(t, filepath.Join(dir, "14-bis_Linux_x86_64.tar.gz"), download.path)
+	assert.Equal(t, filepath.Join(dir, "checksums.txt"), download.checksums)
+	assert.Equal(t, []string{"http://artifacts.json", "http://file-linux-amd64.tar.gz"}, downloads)
+
+	_, actual, err := readChecksum(download.path, download.checksums)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "123", actual)
+}
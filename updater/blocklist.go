@@ -0,0 +1,76 @@
+package updater
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	pvdr "github.com/aureliano/caravela/provider"
+)
+
+const blocklistTimeout = time.Second * 30
+
+// skippedVersions returns the versions that must never be offered: opts.SkipVersions
+// along with the ones listed in the blocklist at opts.BlocklistURL, if any.
+func skippedVersions(client pvdr.HTTPClientPlugin, opts Options) ([]string, error) {
+	skipped := append([]string{}, opts.SkipVersions...)
+	if opts.BlocklistURL == "" {
+		return skipped, nil
+	}
+
+	blocked, err := fetchBlocklist(client, opts.BlocklistURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(skipped, blocked...), nil
+}
+
+// fetchBlocklist downloads a blocklist, a text file with a version per line.
+// Blank lines and lines starting with # are ignored.
+func fetchBlocklist(client pvdr.HTTPClientPlugin, url string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), blocklistTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("blocklist integration error: %d", resp.StatusCode)
+	}
+
+	var versions []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			versions = append(versions, line)
+		}
+	}
+
+	return versions, scanner.Err()
+}
+
+// isSkipped tells whether release is one of the skipped versions. Versions are
+// compared according to scheme, so v1.4.0 is skipped when 1.4.0 is listed.
+func isSkipped(release *pvdr.Release, skipped []string, scheme pvdr.VersionScheme) bool {
+	for _, version := range skipped {
+		if release.CompareWith(&pvdr.Release{Name: version}, scheme) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package updater
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	pvdr "github.com/aureliano/caravela/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func blocklistResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader([]byte(body)))}
+}
+
+func TestSkippedVersions(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	m.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://dl.corp.com/14-bis/blocklist.txt"
+	})).Return(blocklistResponse(http.StatusOK, "# assets deleted\nv0.3.0\n\n  v0.2.1  \n"), nil)
+
+	actual, err := skippedVersions(m, Options{
+		SkipVersions: []string{"v0.2.0"},
+		BlocklistURL: "https://dl.corp.com/14-bis/blocklist.txt",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"v0.2.0", "v0.3.0", "v0.2.1"}, actual)
+}
+
+func TestSkippedVersionsNoBlocklist(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+
+	actual, err := skippedVersions(m, Options{SkipVersions: []string{"v0.2.0"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"v0.2.0"}, actual)
+	m.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestFetchBlocklistError(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	m.On("Do", mock.Anything).Return((*http.Response)(nil), fmt.Errorf("connection refused"))
+
+	_, err := fetchBlocklist(m, "https://dl.corp.com/14-bis/blocklist.txt")
+	assert.Equal(t, "connection refused", err.Error())
+}
+
+func TestFetchBlocklistStatusError(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	m.On("Do", mock.Anything).Return(blocklistResponse(http.StatusNotFound, ""), nil)
+
+	_, err := fetchBlocklist(m, "https://dl.corp.com/14-bis/blocklist.txt")
+	assert.Equal(t, "blocklist integration error: 404", err.Error())
+}
+
+func TestIsSkipped(t *testing.T) {
+	skipped := []string{"0.3.0", "v0.2.1"}
+
+	assert.True(t, isSkipped(&pvdr.Release{Name: "v0.3.0"}, skipped, nil))
+	assert.True(t, isSkipped(&pvdr.Release{Name: "0.2.1"}, skipped, nil))
+	assert.False(t, isSkipped(&pvdr.Release{Name: "v0.2.0"}, skipped, nil))
+	assert.False(t, isSkipped(&pvdr.Release{Name: "v0.3.0"}, nil, nil))
+}
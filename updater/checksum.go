@@ -2,62 +2,236 @@ package updater
 
 import (
 	"crypto/sha256"
-	"encoding/hex"
+	"crypto/sha512"
+	"errors"
 	"fmt"
-	"io"
+	"hash"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/zeebo/blake3"
+	"golang.org/x/crypto/blake2b"
 )
 
-func checksum(binPath, checksumsPath string) error {
-	hasher := sha256.New()
-	checksum, err := getChecksum(binPath, checksumsPath)
-	if err != nil {
-		return err
+// ChecksumAlgorithm is a hash function release files are checksummed with.
+type ChecksumAlgorithm string
+
+// The supported checksum algorithms.
+const (
+	SHA256 ChecksumAlgorithm = "sha256"
+	SHA512 ChecksumAlgorithm = "sha512"
+	// BLAKE2b is BLAKE2b-512, as computed by b2sum.
+	BLAKE2b ChecksumAlgorithm = "blake2b"
+	BLAKE3  ChecksumAlgorithm = "blake3"
+)
+
+// checksumAlgorithms are the supported hash functions, along with their tag in BSD
+// style checksums, e.g. SHA256 (14-bis.tar.gz) = hash, and the suffixes of their
+// sidecar files, e.g. 14-bis.tar.gz.sha256.
+var checksumAlgorithms = []struct {
+	algorithm ChecksumAlgorithm
+	tag       string
+	suffixes  []string
+	newHash   func() hash.Hash
+}{
+	{SHA256, "SHA256", []string{".sha256", ".sha256sum"}, sha256.New},
+	{SHA512, "SHA512", []string{".sha512", ".sha512sum"}, sha512.New},
+	{BLAKE2b, "BLAKE2b", []string{".b2", ".blake2b"}, newBlake2b},
+	{BLAKE3, "BLAKE3", []string{".b3", ".blake3"}, func() hash.Hash { return blake3.New() }},
+}
+
+var (
+	// ErrChecksumNotListed is reported when the release file is not listed in the
+	// checksums, so it can't be verified.
+	ErrChecksumNotListed = errors.New("not listed in the checksums")
+
+	// ErrChecksumMismatch is reported when the digest of the release file is not the
+	// one listed in the checksums.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// VerificationReport records the verification of a release file against its checksum,
+// e.g. to be logged for auditing.
+type VerificationReport struct {
+	// File is the name of the release file.
+	File string
+	// Source is where the expected checksum comes from: the URL of the checksums
+	// file or the asset digest.
+	Source    string
+	Algorithm ChecksumAlgorithm
+	// Expected is the checksum listed for File, empty when it is not listed.
+	Expected string
+	// Actual is the digest of File, computed while it was downloaded.
+	Actual   string
+	Verified bool
+}
+
+// ChecksumError reports a release file that failed verification. Err is either
+// ErrChecksumNotListed or ErrChecksumMismatch.
+type ChecksumError struct {
+	Report VerificationReport
+	Err    error
+}
+
+func (e *ChecksumError) Error() string {
+	if errors.Is(e.Err, ErrChecksumMismatch) {
+		return fmt.Sprintf("%s: %v (%s): expected %s, got %s",
+			e.Report.File, e.Err, e.Report.Algorithm, e.Report.Expected, e.Report.Actual)
 	}
 
-	binFile, err := os.Open(binPath)
-	if err != nil {
-		return err
+	return fmt.Sprintf("%s: %v", e.Report.File, e.Err)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return e.Err
+}
+
+// bsdChecksumRegex matches the lines written by the BSD tools and by the GNU ones
+// with --tag, e.g. SHA256 (14-bis.tar.gz) = hash.
+var bsdChecksumRegex = regexp.MustCompile(`^([A-Za-z0-9-]+) ?\((.+)\) ?= ?([0-9A-Fa-f]+)$`)
+
+// gnuChecksumRegex matches the lines written by the GNU coreutils, e.g. sha256sum,
+// where the name follows two spaces, or a space and * in binary mode.
+var gnuChecksumRegex = regexp.MustCompile(`^\\?([0-9A-Fa-f]+) [ *]?(.+)$`)
+
+var bareChecksumRegex = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+func newBlake2b() hash.Hash {
+	h, _ := blake2b.New512(nil)
+	return h
+}
+
+// newChecksumHash returns a hash computing algorithm, SHA256 when it is empty.
+func newChecksumHash(algorithm ChecksumAlgorithm) (hash.Hash, error) {
+	if algorithm == "" {
+		algorithm = SHA256
 	}
 
-	if _, err = io.Copy(hasher, binFile); err != nil {
-		return err
+	for _, alg := range checksumAlgorithms {
+		if alg.algorithm == algorithm {
+			return alg.newHash(), nil
+		}
 	}
 
-	otherChecksum := hex.EncodeToString(hasher.Sum(nil))
+	return nil, fmt.Errorf("checksum algorithm %s not supported", algorithm)
+}
 
-	if checksum != otherChecksum {
-		return fmt.Errorf("checksum failed")
+// checksumAlgorithmByTag returns the algorithm tagged as tag, or the tag itself when
+// it is not supported, so it gets reported.
+func checksumAlgorithmByTag(tag string) ChecksumAlgorithm {
+	for _, alg := range checksumAlgorithms {
+		if strings.EqualFold(alg.tag, tag) {
+			return alg.algorithm
+		}
 	}
 
-	return nil
+	return ChecksumAlgorithm(tag)
 }
 
-func getChecksum(binPath, checksumsPath string) (string, error) {
-	file, err := os.Open(checksumsPath)
-	if err != nil {
-		return "", err
+// sidecarAlgorithm returns the algorithm of the sidecar checksum file named name, or
+// an empty string if name is not a sidecar file.
+func sidecarAlgorithm(name string) ChecksumAlgorithm {
+	name = strings.ToLower(name)
+	for _, alg := range checksumAlgorithms {
+		for _, suffix := range alg.suffixes {
+			if strings.HasSuffix(name, suffix) {
+				return alg.algorithm
+			}
+		}
+	}
+
+	return ""
+}
+
+// checksum checks the digest of the downloaded release file, computed while it was
+// downloaded, against the one listed in the checksums file, so the release file is
+// not read again. Failures are reported as a ChecksumError.
+func checksum(download releaseDownload) (VerificationReport, error) {
+	report := VerificationReport{
+		File:      filepath.Base(download.path),
+		Source:    download.source,
+		Algorithm: download.algorithm,
+		Actual:    download.digest,
 	}
 
-	bytes, err := (io.ReadAll(file))
+	_, expected, err := readChecksum(download.path, download.checksums)
+	if errors.Is(err, ErrChecksumNotListed) {
+		return report, &ChecksumError{Report: report, Err: ErrChecksumNotListed}
+	} else if err != nil {
+		return report, err
+	}
+
+	report.Expected = expected
+	if !strings.EqualFold(expected, download.digest) {
+		return report, &ChecksumError{Report: report, Err: ErrChecksumMismatch}
+	}
+
+	report.Verified = true
+	return report, nil
+}
+
+// readChecksum returns the checksum of binPath listed in the checksums file, along
+// with its algorithm when the format tells it. GNU coreutils and BSD formats are
+// supported, as well as sidecar files holding a bare checksum. An error wrapping
+// ErrChecksumNotListed is returned when binPath is not listed.
+func readChecksum(binPath, checksumsPath string) (ChecksumAlgorithm, string, error) {
+	bytes, err := os.ReadFile(checksumsPath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	lines := strings.Split(string(bytes), "\n")
-	var checksum string
+	binName := filepath.Base(binPath)
+	var entries []string
 
-	for _, line := range lines {
-		binName := filepath.Base(binPath)
+	for _, line := range strings.Split(string(bytes), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		entries = append(entries, line)
+
+		if match := bsdChecksumRegex.FindStringSubmatch(line); match != nil {
+			if checksumFileName(match[2]) == binName {
+				return checksumAlgorithmByTag(match[1]), match[3], nil
+			}
+		} else if match := gnuChecksumRegex.FindStringSubmatch(line); match != nil {
+			if checksumFileName(match[2]) == binName {
+				return "", match[1], nil
+			}
+		}
+	}
+
+	// A sidecar file holds the checksum of a single file.
+	if len(entries) == 1 && bareChecksumRegex.MatchString(entries[0]) {
+		return "", entries[0], nil
+	}
+
+	return "", "", fmt.Errorf("%s: %w", binName, ErrChecksumNotListed)
+}
+
+// checksumFileName returns the base of a file name listed in a checksums file,
+// e.g. 14-bis.tar.gz for ./dist/14-bis.tar.gz.
+func checksumFileName(name string) string {
+	return path.Base(filepath.ToSlash(strings.TrimPrefix(name, "*")))
+}
+
+// writeDigestChecksums writes a checksums file with the digest of an asset, e.g.
+// sha512:hash, so assets that carry their own digest are verified like any other.
+func writeDigestChecksums(digest, binName, dest string) error {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("digest %s not supported", digest)
+	}
 
-		if strings.HasSuffix(line, fmt.Sprintf(" %s", binName)) {
-			columns := strings.Split(line, " ")
-			checksum = columns[0]
-			break
+	for _, alg := range checksumAlgorithms {
+		if string(alg.algorithm) == strings.ToLower(parts[0]) {
+			content := fmt.Sprintf("%s (%s) = %s\n", alg.tag, binName, parts[1])
+			return os.WriteFile(dest, []byte(content), 0600)
 		}
 	}
 
-	return checksum, nil
+	return fmt.Errorf("digest %s not supported", digest)
 }
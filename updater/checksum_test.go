@@ -1,91 +1,239 @@
 package updater
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/hex"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
-func TestChecksumBinNotFound(t *testing.T) {
+func TestChecksumBinNotRead(t *testing.T) {
 	file, err := os.Create(filepath.Join(os.TempDir(), "checksums.txt"))
 	assert.Nil(t, err, err)
 
 	_, _ = file.WriteString("dc173fa63edc62745edaa05422a3f2d7413d36b52f10d9e6623a8a946b8792db 14-bis_Linux_x86_64.zip")
 	file.Close()
 
-	err = checksum("/no/file", file.Name())
-	assert.NotNil(t, err)
+	digest := "dc173fa63edc62745edaa05422a3f2d7413d36b52f10d9e6623a8a946b8792db"
+	_, err = checksum(releaseDownload{path: "/no/14-bis_Linux_x86_64.zip", digest: digest, checksums: file.Name()})
+	assert.Nil(t, err, err)
 }
 
 func TestChecksumChecksumsNotFound(t *testing.T) {
-	err := checksum("/no/file", "/no/file")
+	_, err := checksum(releaseDownload{path: "/no/file", digest: "12345", checksums: "/no/file"})
 	assert.NotNil(t, err)
 }
 
-func TestChecksumDoesntMatch(t *testing.T) {
-	zip := filepath.Join(os.TempDir(), "14-bis_Linux_x86_64")
-	err := createZipFile(zip)
-	zip = fmt.Sprintf("%s.zip", zip)
+func TestChecksumNotListed(t *testing.T) {
+	file, err := os.Create(filepath.Join(os.TempDir(), "checksums.txt"))
 	assert.Nil(t, err, err)
 
+	_, _ = file.WriteString("12345 14-bis_Windows_x86_64.zip")
+	file.Close()
+
+	report, err := checksum(releaseDownload{
+		path:      filepath.Join(os.TempDir(), "14-bis_Linux_x86_64.zip"),
+		digest:    "12345",
+		algorithm: SHA256,
+		checksums: file.Name(),
+		source:    "http://checksums.txt",
+	})
+
+	var checksumErr *ChecksumError
+	assert.ErrorAs(t, err, &checksumErr)
+	assert.ErrorIs(t, err, ErrChecksumNotListed)
+	assert.Equal(t, "14-bis_Linux_x86_64.zip: not listed in the checksums", err.Error())
+	assert.Equal(t, report, checksumErr.Report)
+	assert.Equal(t, VerificationReport{
+		File:      "14-bis_Linux_x86_64.zip",
+		Source:    "http://checksums.txt",
+		Algorithm: SHA256,
+		Actual:    "12345",
+	}, report)
+}
+
+func TestChecksumDoesntMatch(t *testing.T) {
 	file, err := os.Create(filepath.Join(os.TempDir(), "checksums.txt"))
 	assert.Nil(t, err, err)
 
 	_, _ = file.WriteString("12345 14-bis_Linux_x86_64.zip")
 	file.Close()
 
-	err = checksum(zip, file.Name())
+	zip := filepath.Join(os.TempDir(), "14-bis_Linux_x86_64.zip")
+	report, err := checksum(releaseDownload{path: zip, digest: "abcdef", algorithm: SHA256, checksums: file.Name()})
 	actual := err.Error()
-	expected := "checksum failed"
+	expected := "14-bis_Linux_x86_64.zip: checksum mismatch (sha256): expected 12345, got abcdef"
 	assert.Equal(t, expected, actual)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Equal(t, "12345", report.Expected)
+	assert.Equal(t, "abcdef", report.Actual)
+	assert.False(t, report.Verified)
 }
 
 func TestChecksum(t *testing.T) {
-	zip := filepath.Join(os.TempDir(), "14-bis_Linux_x86_64")
-	err := createZipFile(zip)
-	zip = fmt.Sprintf("%s.zip", zip)
+	m := new(mockHTTPPlugin)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte("12345"))),
+		}, nil)
+
+	zip := filepath.Join(os.TempDir(), "14-bis_Linux_x86_64.zip")
+	digest, err := downloadFile(m, "http://file-linux.zip", zip, SHA256)
 	assert.Nil(t, err, err)
 
 	file, err := os.Create(filepath.Join(os.TempDir(), "checksums.txt"))
 	assert.Nil(t, err, err)
 
-	_, _ = file.WriteString("dc173fa63edc62745edaa05422a3f2d7413d36b52f10d9e6623a8a946b8792db 14-bis_Linux_x86_64.zip")
+	_, _ = file.WriteString("5994471abb01112afcc18159f6cc74b4f511b99806da59b3caf5a9c173cacfc5 14-bis_Linux_x86_64.zip")
 	file.Close()
 
-	err = checksum(zip, file.Name())
+	report, err := checksum(releaseDownload{path: zip, digest: digest, algorithm: SHA256, checksums: file.Name()})
 	assert.Nil(t, err, err)
+	assert.True(t, report.Verified)
+	assert.Equal(t, report.Expected, report.Actual)
 }
 
-func TestGetChecksumFileNotFound(t *testing.T) {
-	_, err := getChecksum("/some/path/unexisting.zip", "/some/path/unexisting.txt")
+func TestReadChecksumFileNotFound(t *testing.T) {
+	_, _, err := readChecksum("/some/path/unexisting.zip", "/some/path/unexisting.txt")
 	assert.NotNil(t, err)
 }
 
-func TestGetChecksumDoesntMatch(t *testing.T) {
+func TestReadChecksumDoesntMatch(t *testing.T) {
 	file, err := os.Create(filepath.Join(os.TempDir(), "checksums.txt"))
 	assert.Nil(t, err, err)
 
 	_, _ = file.WriteString("12345 14-bis_Windows_x86_64.zip")
 	file.Close()
 
-	actual, err := getChecksum("14-bis_Linux_x86_64.zip", file.Name())
+	_, actual, err := readChecksum("14-bis_Linux_x86_64.zip", file.Name())
 	expected := ""
-	assert.Nil(t, err, err)
+	assert.ErrorIs(t, err, ErrChecksumNotListed)
 	assert.Equal(t, expected, actual)
 }
 
-func TestGetChecksum(t *testing.T) {
+func TestReadChecksum(t *testing.T) {
 	file, err := os.Create(filepath.Join(os.TempDir(), "checksums.txt"))
 	assert.Nil(t, err, err)
 
 	_, _ = file.WriteString("12345 14-bis_Linux_x86_64.zip")
 	file.Close()
 
-	actual, err := getChecksum("14-bis_Linux_x86_64.zip", file.Name())
+	_, actual, err := readChecksum("14-bis_Linux_x86_64.zip", file.Name())
 	expected := "12345"
 	assert.Nil(t, err, err)
 	assert.Equal(t, expected, actual)
 }
+
+func TestWriteDigestChecksumsUnsupported(t *testing.T) {
+	dest := filepath.Join(os.TempDir(), "checksums.txt")
+	err := writeDigestChecksums("md5:12345", "14-bis_Linux_x86_64.zip", dest)
+	assert.Equal(t, "digest md5:12345 not supported", err.Error())
+}
+
+func TestWriteDigestChecksumsSHA512(t *testing.T) {
+	dest := filepath.Join(os.TempDir(), "checksums.txt")
+	err := writeDigestChecksums("sha512:12345", "14-bis_Linux_x86_64.zip", dest)
+	assert.Nil(t, err, err)
+
+	algorithm, actual, err := readChecksum("14-bis_Linux_x86_64.zip", dest)
+	assert.Nil(t, err, err)
+	assert.Equal(t, SHA512, algorithm)
+	assert.Equal(t, "12345", actual)
+}
+
+func TestWriteDigestChecksums(t *testing.T) {
+	dest := filepath.Join(os.TempDir(), "checksums.txt")
+	err := writeDigestChecksums("sha256:12345", "14-bis_Linux_x86_64.zip", dest)
+	assert.Nil(t, err, err)
+
+	_, actual, err := readChecksum("14-bis_Linux_x86_64.zip", dest)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "12345", actual)
+}
+
+func TestNewChecksumHash(t *testing.T) {
+	digests := map[ChecksumAlgorithm]string{
+		"":     "5994471abb01112afcc18159f6cc74b4f511b99806da59b3caf5a9c173cacfc5",
+		SHA256: "5994471abb01112afcc18159f6cc74b4f511b99806da59b3caf5a9c173cacfc5",
+		SHA512: "3627909a29c31381a071ec27f7c9ca97726182aed29a7ddd2e54353322cfb30a" +
+			"bb9e3a6df2ac2c20fe23436311d678564d0c8d305930575f60e2d3d048184d79",
+		BLAKE2b: "8b28f613fa1ccdb1d303704839a0bb196424f425badfa4e4f43808f6812b6bcc" +
+			"0ae43374383bb6e46294d08155a64acbad92084387c73f696f00368ea106ebb4",
+		BLAKE3: "86f2d80abe9c3f7b4a1a57a8d1130fa8dc08c81604833ce1212dc039b010d9e4",
+	}
+
+	for algorithm, expected := range digests {
+		h, err := newChecksumHash(algorithm)
+		assert.Nil(t, err, err)
+
+		_, _ = h.Write([]byte("12345"))
+		assert.Equal(t, expected, hex.EncodeToString(h.Sum(nil)), algorithm)
+	}
+
+	_, err := newChecksumHash("md5")
+	assert.Equal(t, "checksum algorithm md5 not supported", err.Error())
+}
+
+func TestReadChecksumFormats(t *testing.T) {
+	type testCase struct {
+		name      string
+		content   string
+		algorithm ChecksumAlgorithm
+		checksum  string
+	}
+
+	testCases := []testCase{
+		{"gnu text mode", "abc123  14-bis_Linux_x86_64.zip\n", "", "abc123"},
+		{"gnu binary mode", "abc123 *14-bis_Linux_x86_64.zip\n", "", "abc123"},
+		{"single space", "abc123 14-bis_Linux_x86_64.zip", "", "abc123"},
+		{"crlf", "fff 14-bis_Windows_x86_64.zip\r\nabc123  14-bis_Linux_x86_64.zip\r\n", "", "abc123"},
+		{"path", "abc123  ./dist/14-bis_Linux_x86_64.zip\n", "", "abc123"},
+		{"bsd", "SHA512 (14-bis_Linux_x86_64.zip) = abc123\n", SHA512, "abc123"},
+		{"b2sum tag", "BLAKE2b (14-bis_Linux_x86_64.zip) = abc123\n", BLAKE2b, "abc123"},
+		{"bsd unsupported", "MD5 (14-bis_Linux_x86_64.zip) = abc123\n", "MD5", "abc123"},
+		{"sidecar", "ABC123\n", "", "ABC123"},
+	}
+
+	dest := filepath.Join(os.TempDir(), "checksums.txt")
+	for _, tc := range testCases {
+		err := os.WriteFile(dest, []byte(tc.content), 0600)
+		assert.Nil(t, err, err)
+
+		algorithm, checksum, err := readChecksum("/tmp/14-bis_Linux_x86_64.zip", dest)
+		assert.Nil(t, err, err)
+		assert.Equal(t, tc.algorithm, algorithm, tc.name)
+		assert.Equal(t, tc.checksum, checksum, tc.name)
+	}
+
+	for _, content := range []string{"abc123  14-bis_Windows_x86_64.zip\n", "abc123\nfff\n", ""} {
+		err := os.WriteFile(dest, []byte(content), 0600)
+		assert.Nil(t, err, err)
+
+		_, _, err = readChecksum("/tmp/14-bis_Linux_x86_64.zip", dest)
+		assert.ErrorIs(t, err, ErrChecksumNotListed, content)
+	}
+}
+
+func TestChecksumIgnoresCase(t *testing.T) {
+	dest := filepath.Join(os.TempDir(), "checksums.txt")
+	err := os.WriteFile(dest, []byte("ABC123  14-bis_Linux_x86_64.zip"), 0600)
+	assert.Nil(t, err, err)
+
+	_, err = checksum(releaseDownload{path: "14-bis_Linux_x86_64.zip", digest: "abc123", checksums: dest})
+	assert.Nil(t, err, err)
+}
+
+func TestSidecarAlgorithm(t *testing.T) {
+	assert.Equal(t, SHA256, sidecarAlgorithm("14-bis.tar.gz.sha256"))
+	assert.Equal(t, SHA512, sidecarAlgorithm("14-bis.tar.gz.SHA512"))
+	assert.Equal(t, BLAKE2b, sidecarAlgorithm("14-bis.tar.gz.b2"))
+	assert.Equal(t, BLAKE3, sidecarAlgorithm("14-bis.tar.gz.blake3"))
+	assert.Equal(t, ChecksumAlgorithm(""), sidecarAlgorithm("14-bis.tar.gz"))
+}
@@ -3,6 +3,9 @@ package updater
 import (
 	"archive/tar"
 	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
 	"compress/gzip"
 	"errors"
 	"fmt"
@@ -11,71 +14,309 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
-func decompress(src string) (int, error) {
-	switch {
-	case strings.HasSuffix(src, ".zip"):
-		return unzip(src)
-	case strings.HasSuffix(src, ".tar.gz") || strings.HasSuffix(src, ".tgz"):
-		return ungzip(src)
-	default:
+const (
+	formatZip   = "zip"
+	formatTar   = "tar"
+	formatGzip  = "gzip"
+	formatXz    = "xz"
+	formatZstd  = "zstd"
+	formatBzip2 = "bzip2"
+)
+
+// tarMagicOffset is the offset of the ustar magic within the header of a tar file.
+const tarMagicOffset = 257
+
+var tarMagic = []byte("ustar")
+
+const permDir = 0755
+
+// archiveMagics are the magic bytes that archive files start with.
+var archiveMagics = []struct {
+	format string
+	magic  []byte
+}{
+	{formatZip, []byte("PK\x03\x04")},
+	{formatGzip, []byte{0x1f, 0x8b}},
+	{formatXz, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+	{formatZstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{formatBzip2, []byte("BZh")},
+}
+
+// archiveSuffixes are the suffixes of archive names, e.g. .gz for both .tar.gz and
+// single-file .gz archives.
+var archiveSuffixes = []struct {
+	format string
+	suffix string
+}{
+	{formatZip, ".zip"},
+	{formatTar, ".tar"},
+	{formatGzip, ".gz"},
+	{formatGzip, ".tgz"},
+	{formatXz, ".xz"},
+	{formatXz, ".txz"},
+	{formatZstd, ".zst"},
+	{formatZstd, ".tzst"},
+	{formatBzip2, ".bz2"},
+	{formatBzip2, ".tbz2"},
+	{formatBzip2, ".tbz"},
+}
+
+// isArchive tells whether the release file named name is an archive to be decompressed.
+func isArchive(name string) bool {
+	return archiveFormatByName(name) != ""
+}
+
+// isArchiveFile tells whether the file at path is an archive, judging by its magic
+// bytes or, if they are not known, by its name.
+func isArchiveFile(path string) bool {
+	return archiveFormat(path) != ""
+}
+
+// decompression tells what was extracted from an archive.
+type decompression struct {
+	// files is the number of files extracted.
+	files int
+	// single is the path of the file decompressed from a single compressed file,
+	// e.g. 14-bis.gz, which is the executable itself. It is empty for archives.
+	single string
+}
+
+// decompress extracts the archive src into its directory. The format is detected from
+// the magic bytes of src, so mislabeled archives are supported, and from its name
+// otherwise. Compressed tar files as well as single compressed files, e.g. 14-bis.gz,
+// are supported.
+//
+// Extraction is bounded by limits and refused entries are reported as an ExtractionError.
+func decompress(src string, limits extractionLimits) (decompression, error) {
+	var files int
+	var err error
+
+	switch format := archiveFormat(src); format {
+	case formatZip:
+		files, err = unzip(src, limits)
+	case formatTar:
+		files, err = untarFile(src, limits)
+	case "":
 		ext := filepath.Ext(src)
-		return 0, fmt.Errorf("%s not supported for decompression", ext)
+		return decompression{}, fmt.Errorf("%s not supported for decompression", ext)
+	default:
+		return decompressFile(src, format, limits)
+	}
+
+	return decompression{files: files}, err
+}
+
+func archiveFormat(path string) string {
+	if format := archiveFormatByMagic(path); format != "" {
+		return format
+	}
+
+	return archiveFormatByName(path)
+}
+
+func archiveFormatByMagic(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	header := make([]byte, tarMagicOffset+len(tarMagic))
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+
+	for _, archive := range archiveMagics {
+		if bytes.HasPrefix(header, archive.magic) {
+			return archive.format
+		}
+	}
+
+	if isTarHeader(header) {
+		return formatTar
 	}
+
+	return ""
+}
+
+func archiveFormatByName(name string) string {
+	name = strings.ToLower(name)
+	for _, archive := range archiveSuffixes {
+		if strings.HasSuffix(name, archive.suffix) {
+			return archive.format
+		}
+	}
+
+	return ""
 }
 
-func unzip(src string) (int, error) {
+func isTarHeader(header []byte) bool {
+	return len(header) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(header[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic)
+}
+
+// gzipFileName returns the base of the file name recorded in a gzip header,
+// or an empty string if there is none.
+func gzipFileName(name string) string {
+	base := filepath.Base(filepath.FromSlash(name))
+	if name == "" || base == "." || base == ".." || base == string(filepath.Separator) {
+		return ""
+	}
+
+	return base
+}
+
+// unzip extracts the zip archive src into its directory. Directories, regular files
+// and symbolic links are supported.
+func unzip(src string, limits extractionLimits) (int, error) {
 	r, err := zip.OpenReader(src)
 	if err != nil {
 		return 0, err
 	}
 	defer r.Close()
 
-	dir := filepath.Dir(src)
+	e := newExtractor(filepath.Dir(src), limits)
 	counter := 0
 	for _, file := range r.File {
-		in, e := file.Open()
-		if e != nil {
-			return counter, e
+		if err = unzipFile(e, file); err != nil {
+			return counter, err
 		}
-		defer in.Close()
 
-		if strings.Contains(dir, "..") || strings.Contains(file.Name, "..") {
-			return counter, fmt.Errorf("err")
-		}
+		counter++
+	}
+
+	return counter, nil
+}
+
+func unzipFile(e *extractor, file *zip.File) error {
+	path, err := e.entry(file.Name)
+	if err != nil {
+		return err
+	}
+
+	mode := file.Mode()
+	switch {
+	case mode.IsDir():
+		return e.dir(path)
+	case mode&(fs.ModeDevice|fs.ModeCharDevice|fs.ModeNamedPipe|fs.ModeSocket) != 0:
+		return &ExtractionError{Entry: file.Name, Err: ErrSpecialFile}
+	}
 
-		path := filepath.Join(dir, filepath.Clean(file.Name))
-		_, e = writeFile(path, in, file.Mode())
-		if e != nil {
-			return counter, e
+	in, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if mode&fs.ModeSymlink != 0 {
+		const maxLinkSize = 4096
+		target, err := io.ReadAll(io.LimitReader(in, maxLinkSize))
+		if err != nil {
+			return err
 		}
 
-		counter++
+		return e.symlink(file.Name, path, string(target))
 	}
 
-	return counter, nil
+	return e.file(file.Name, path, in, mode.Perm())
 }
 
-func ungzip(src string) (int, error) {
+func untarFile(src string, limits extractionLimits) (int, error) {
 	srcFile, err := os.Open(src)
 	if err != nil {
 		return 0, err
 	}
 	defer srcFile.Close()
 
-	reader, err := gzip.NewReader(srcFile)
+	return untar(filepath.Dir(src), srcFile, limits)
+}
+
+// decompressFile decompresses src, compressed with format, into its directory.
+// A tar file is extracted; any other content is written as a single executable
+// file, named after the gzip header or after src without its suffix.
+func decompressFile(src, format string, limits extractionLimits) (decompression, error) {
+	srcFile, err := os.Open(src)
 	if err != nil {
-		return 0, err
+		return decompression{}, err
 	}
+	defer srcFile.Close()
+
+	reader, name, err := openDecompressor(format, srcFile)
+	if err != nil {
+		return decompression{}, err
+	}
+	defer reader.Close()
 
 	dir := filepath.Dir(src)
-	return untar(dir, reader)
+	buffered := bufio.NewReader(reader)
+	if header, _ := buffered.Peek(tarMagicOffset + len(tarMagic)); isTarHeader(header) {
+		files, err := untar(dir, buffered, limits)
+		return decompression{files: files}, err
+	}
+
+	if name == "" {
+		base := filepath.Base(src)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+		if name == base {
+			return decompression{}, fmt.Errorf("unable to name the file decompressed from %s", base)
+		}
+	}
+
+	e := newExtractor(dir, limits)
+	path, err := e.entry(name)
+	if err != nil {
+		return decompression{}, err
+	}
+
+	const permExecutable = 0755
+	if err = e.file(name, path, buffered, permExecutable); err != nil {
+		return decompression{}, err
+	}
+
+	return decompression{files: 1, single: path}, nil
+}
+
+// openDecompressor returns a reader of the content of in, compressed with format,
+// along with the original file name, if the format records it.
+func openDecompressor(format string, in io.Reader) (io.ReadCloser, string, error) {
+	switch format {
+	case formatGzip:
+		reader, err := gzip.NewReader(in)
+		if err != nil {
+			return nil, "", err
+		}
+		return reader, gzipFileName(reader.Name), nil
+	case formatXz:
+		reader, err := xz.NewReader(in)
+		if err != nil {
+			return nil, "", err
+		}
+		return io.NopCloser(reader), "", nil
+	case formatZstd:
+		decoder, err := zstd.NewReader(in)
+		if err != nil {
+			return nil, "", err
+		}
+		return decoder.IOReadCloser(), "", nil
+	case formatBzip2:
+		return io.NopCloser(bzip2.NewReader(in)), "", nil
+	default:
+		return nil, "", fmt.Errorf("%s not supported for decompression", format)
+	}
 }
 
-func untar(dir string, in io.Reader) (int, error) {
+// untar extracts the tar stream in into dir. Directories, regular files, symbolic
+// links and hard links are supported. Permissions and modification times of the
+// entries are preserved.
+func untar(dir string, in io.Reader, limits extractionLimits) (int, error) {
 	tarReader := tar.NewReader(in)
+	e := newExtractor(dir, limits)
 	counter := 0
+	var dirs []*tar.Header
 
 	for {
 		header, err := tarReader.Next()
@@ -87,49 +328,53 @@ func untar(dir string, in io.Reader) (int, error) {
 		}
 
 		name := header.Name
+		path, err := e.entry(name)
+		if err != nil {
+			return counter, err
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
-			{
-				if err = os.Mkdir(name, 0755); err != nil {
-					return counter, err
-				}
-			}
+			err = e.dir(path)
+			dirs = append(dirs, header)
 		case tar.TypeReg:
-			{
-				cname := filepath.Clean(name)
-				path := filepath.Join(dir, cname)
-				virtualDir := filepath.Dir(cname)
-				if virtualDir != "." {
-					err = os.MkdirAll(filepath.Join(dir, virtualDir), fs.ModePerm)
-					if err != nil {
-						return counter, err
-					}
-				}
-
-				_, err = writeFile(path, tarReader, fs.FileMode(header.Mode))
-				if err != nil {
-					return counter, err
-				}
+			err = e.file(name, path, tarReader, header.FileInfo().Mode().Perm())
+			if err == nil {
+				err = os.Chtimes(path, header.ModTime, header.ModTime)
 			}
+		case tar.TypeSymlink:
+			err = e.symlink(name, path, header.Linkname)
+		case tar.TypeLink:
+			err = e.hardlink(name, path, header.Linkname)
+		case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+			err = &ExtractionError{Entry: name, Err: ErrSpecialFile}
 		default:
-			return counter, fmt.Errorf("%s has unknown type (%v?)", name, header.Typeflag)
+			err = fmt.Errorf("%s has unknown type (%v?)", name, header.Typeflag)
+		}
+
+		if err != nil {
+			return counter, err
 		}
 
 		counter++
 	}
 
-	return counter, nil
-}
+	// Directories get their permissions and times last, as they may not be writable
+	// and extracting their content updates their times.
+	for _, header := range dirs {
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if info, err := os.Lstat(path); err != nil || !info.IsDir() || e.throughSymlink(path) {
+			return counter, &ExtractionError{Entry: header.Name, Err: ErrSymlinkTraversal}
+		}
 
-func writeFile(dest string, in io.Reader, perm fs.FileMode) (string, error) {
-	out, err := os.OpenFile(dest, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
-	if err != nil {
-		return "", err
-	}
+		if err := os.Chmod(path, header.FileInfo().Mode().Perm()); err != nil {
+			return counter, err
+		}
 
-	_, err = io.Copy(out, in)
-	out.Close()
+		if err := os.Chtimes(path, header.ModTime, header.ModTime); err != nil {
+			return counter, err
+		}
+	}
 
-	return dest, err
+	return counter, nil
 }
@@ -3,19 +3,27 @@ package updater
 import (
 	"archive/tar"
 	"archive/zip"
+	"bytes"
 	"compress/gzip"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
 )
 
 func TestDecompressUnsupportedType(t *testing.T) {
-	done, err := decompress("file.rar")
-	assert.True(t, done == 0)
+	done, err := decompress("file.rar", extractionLimits{})
+	assert.Equal(t, decompression{}, done)
 
 	actual := err.Error()
 	expected := ".rar not supported for decompression"
@@ -28,8 +36,8 @@ func TestDecompressZip(t *testing.T) {
 	zip = fmt.Sprintf("%s.zip", zip)
 	assert.Nil(t, err, err)
 
-	actual, err := decompress(zip)
-	expected := 2
+	actual, err := decompress(zip, extractionLimits{})
+	expected := decompression{files: 2}
 
 	assert.Nil(t, err, err)
 	assert.Equal(t, expected, actual)
@@ -43,16 +51,186 @@ func TestDecompressUngzip(t *testing.T) {
 	tgz = fmt.Sprintf("%s.tar.gz", tgz)
 	assert.Nil(t, err, err)
 
-	actual, err := decompress(tgz)
-	expected := 2
+	actual, err := decompress(tgz, extractionLimits{})
+	expected := decompression{files: 2}
 	assert.Nil(t, err, err)
 	assert.Equal(t, expected, actual)
 
 	os.Remove(tgz)
 }
 
+// tarBzip2Base64 is a tar.bz2 archive holding the files 14-bis and README.md.
+const tarBzip2Base64 = "QlpoOTFBWSZTWQpKjY0AAID/gMoQAgBoA/+AJgIQkHRjHgAICCAAlISpoTTUyDACZG0DQJKU" +
+	"yMmAJ6JkyYGjR+bqAgImAAzykhDWuLfEgQjWyEYhDBbTQKqOicys5tJhQ8QA0gwB4NPVxe9w" +
+	"2QzKR7RAqWIMskWtxLmLcjKP2kIWuCJrAjlyreMRCXo9DyUSDsXckU4UJAKSo2NA"
+
+func tarFile(t *testing.T) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range []string{"14-bis", "README.md"} {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 3, Typeflag: tar.TypeReg})
+		assert.Nil(t, err)
+		_, err = tw.Write([]byte("bis"))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+
+	return buf.Bytes()
+}
+
+func compressFile(t *testing.T, format string, content []byte) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+
+	switch format {
+	case formatGzip:
+		w = gzip.NewWriter(&buf)
+	case formatXz:
+		w, err = xz.NewWriter(&buf)
+	case formatZstd:
+		w, err = zstd.NewWriter(&buf)
+	default:
+		data, e := base64.StdEncoding.DecodeString(tarBzip2Base64)
+		assert.Nil(t, e)
+		return data
+	}
+	assert.Nil(t, err)
+
+	_, err = w.Write(content)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestDecompressFormats(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format string
+	}{
+		{"14-bis_Linux_x86_64.tar.gz", formatGzip},
+		{"14-bis_Linux_x86_64.tgz", formatGzip},
+		{"14-bis_Linux_x86_64.tar.xz", formatXz},
+		{"14-bis_Linux_x86_64.tar.zst", formatZstd},
+		{"14-bis_Linux_x86_64.tar.bz2", formatBzip2},
+		{"14-bis_Linux_x86_64.tar", formatTar},
+		{"14-bis_Linux_x86_64.zip", formatZstd},
+		{"14-bis_Linux_x86_64", formatXz},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			content := tarFile(t)
+			if tc.format != formatTar {
+				content = compressFile(t, tc.format, content)
+			}
+
+			src := filepath.Join(dir, tc.name)
+			assert.Nil(t, os.WriteFile(src, content, 0600))
+
+			actual, err := decompress(src, extractionLimits{})
+			assert.Nil(t, err, err)
+			assert.Equal(t, decompression{files: 2}, actual)
+			assert.FileExists(t, filepath.Join(dir, "14-bis"))
+			assert.FileExists(t, filepath.Join(dir, "README.md"))
+		})
+	}
+}
+
+func TestDecompressSingleFile(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "14-bis_Linux_x86_64.zst")
+	assert.Nil(t, os.WriteFile(src, compressFile(t, formatZstd, []byte("#!/bin/sh\n")), 0600))
+
+	actual, err := decompress(src, extractionLimits{})
+	assert.Nil(t, err, err)
+	assert.Equal(t, decompression{files: 1, single: filepath.Join(dir, "14-bis_Linux_x86_64")}, actual)
+
+	data, err := os.ReadFile(filepath.Join(dir, "14-bis_Linux_x86_64"))
+	assert.Nil(t, err)
+	assert.Equal(t, "#!/bin/sh\n", string(data))
+
+	info, err := os.Stat(filepath.Join(dir, "14-bis_Linux_x86_64"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestDecompressSingleFileGzipHeaderName(t *testing.T) {
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	gw.Name = "../14-bis"
+	_, _ = gw.Write([]byte("bis"))
+	assert.Nil(t, gw.Close())
+
+	src := filepath.Join(dir, "download")
+	assert.Nil(t, os.WriteFile(src, buf.Bytes(), 0600))
+
+	actual, err := decompress(src, extractionLimits{})
+	assert.Nil(t, err, err)
+	assert.Equal(t, decompression{files: 1, single: filepath.Join(dir, "14-bis")}, actual)
+	assert.FileExists(t, filepath.Join(dir, "14-bis"))
+}
+
+func TestDecompressSingleFileUnnamed(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "download")
+	assert.Nil(t, os.WriteFile(src, compressFile(t, formatXz, []byte("bis")), 0600))
+
+	_, err := decompress(src, extractionLimits{})
+	assert.Equal(t, "unable to name the file decompressed from download", err.Error())
+}
+
+func TestDecompressCorruptedFile(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "14-bis_Linux_x86_64.tar.xz")
+	assert.Nil(t, os.WriteFile(src, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x01}, 0600))
+
+	_, err := decompress(src, extractionLimits{})
+	assert.NotNil(t, err)
+}
+
+func TestIsArchive(t *testing.T) {
+	names := []string{"a.zip", "a.tar", "a.tar.gz", "a.tgz", "a.gz", "a.tar.xz", "a.txz", "a.tar.zst", "a.TAR.BZ2"}
+	for _, name := range names {
+		assert.True(t, isArchive(name), name)
+	}
+
+	for _, name := range []string{"", "a", "a.exe", "a.rar", "checksums.txt"} {
+		assert.False(t, isArchive(name), name)
+	}
+}
+
+func TestIsArchiveFile(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "14-bis")
+	assert.Nil(t, os.WriteFile(src, compressFile(t, formatZstd, []byte("bis")), 0600))
+	assert.True(t, isArchiveFile(src))
+
+	assert.Nil(t, os.WriteFile(src, []byte("\x7fELF"), 0600))
+	assert.False(t, isArchiveFile(src))
+
+	assert.True(t, isArchiveFile(filepath.Join(dir, "unknown.tar.gz")))
+}
+
+func TestGzipFileName(t *testing.T) {
+	assert.Equal(t, "14-bis", gzipFileName("14-bis"))
+	assert.Equal(t, "14-bis", gzipFileName("dist/14-bis"))
+	assert.Equal(t, "14-bis", gzipFileName("../../14-bis"))
+	assert.Empty(t, gzipFileName(""))
+	assert.Empty(t, gzipFileName(".."))
+	assert.Empty(t, gzipFileName("/"))
+}
+
 func TestUnzipInvalidSrc(t *testing.T) {
-	_, err := unzip(filepath.Join("unknown", "path"))
+	_, err := unzip(filepath.Join("unknown", "path"), extractionLimits{})
 	assert.NotNil(t, err)
 }
 
@@ -62,7 +240,7 @@ func TestUnzip(t *testing.T) {
 	zip = fmt.Sprintf("%s.zip", zip)
 	assert.Nil(t, err, err)
 
-	actual, err := unzip(zip)
+	actual, err := unzip(zip, extractionLimits{})
 	expected := 2
 
 	assert.Nil(t, err, err)
@@ -71,19 +249,19 @@ func TestUnzip(t *testing.T) {
 	os.Remove(zip)
 }
 
-func TestUngzipInvalidSrc(t *testing.T) {
-	_, err := ungzip(filepath.Join("unknown", "path"))
+func TestDecompressFileInvalidSrc(t *testing.T) {
+	_, err := decompressFile(filepath.Join("unknown", "path"), formatGzip, extractionLimits{})
 	assert.NotNil(t, err)
 }
 
-func TestUngzip(t *testing.T) {
+func TestDecompressFileTar(t *testing.T) {
 	tgz := filepath.Join(os.TempDir(), "14-bis_Linux_x86_64")
 	err := createGZipFile(tgz)
 	tgz = fmt.Sprintf("%s.tar.gz", tgz)
 	assert.Nil(t, err, err)
 
-	actual, err := ungzip(tgz)
-	expected := 2
+	actual, err := decompressFile(tgz, formatGzip, extractionLimits{})
+	expected := decompression{files: 2}
 
 	assert.Nil(t, err, err)
 	assert.Equal(t, expected, actual)
@@ -91,22 +269,353 @@ func TestUngzip(t *testing.T) {
 	os.Remove(tgz)
 }
 
-func TestWriteFileInvalidDest(t *testing.T) {
-	dest := filepath.Join("unknown", "path")
-	_, err := writeFile(dest, nil, 0666)
-	assert.NotNil(t, err)
+func tarEntries(t *testing.T, headers ...*tar.Header) io.Reader {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, header := range headers {
+		assert.Nil(t, tw.WriteHeader(header))
+		if header.Typeflag == tar.TypeReg {
+			_, err := tw.Write(make([]byte, header.Size))
+			assert.Nil(t, err)
+		}
+	}
+	assert.Nil(t, tw.Close())
+
+	return &buf
 }
 
-func TestWriteFile(t *testing.T) {
-	in, _ := os.CreateTemp("", "test-write-file-*")
-	dest := filepath.Join(os.TempDir(), "test-dest-write-file")
-	path, err := writeFile(dest, in, 0666)
+func TestUntarDirectoriesAndLinks(t *testing.T) {
+	dir := t.TempDir()
+	mtime := time.Date(2023, 3, 9, 14, 11, 18, 0, time.UTC)
+
+	in := tarEntries(t,
+		&tar.Header{Name: "14-bis/", Typeflag: tar.TypeDir, Mode: 0750, ModTime: mtime},
+		&tar.Header{Name: "14-bis/bin/", Typeflag: tar.TypeDir, Mode: 0555, ModTime: mtime},
+		&tar.Header{Name: "14-bis/bin/14-bis", Typeflag: tar.TypeReg, Mode: 0755, Size: 3, ModTime: mtime},
+		&tar.Header{Name: "14-bis/README.md", Typeflag: tar.TypeReg, Mode: 0600, Size: 1, ModTime: mtime},
+		&tar.Header{Name: "14-bis/14-bis", Typeflag: tar.TypeSymlink, Linkname: "bin/14-bis", ModTime: mtime},
+		&tar.Header{Name: "14-bis/LEIAME.md", Typeflag: tar.TypeLink, Linkname: "14-bis/README.md", ModTime: mtime},
+	)
 
+	actual, err := untar(dir, in, extractionLimits{})
 	assert.Nil(t, err, err)
-	assert.Equal(t, path, dest)
+	assert.Equal(t, 6, actual)
+
+	info, err := os.Stat(filepath.Join(dir, "14-bis", "bin"))
+	assert.Nil(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, os.FileMode(0555), info.Mode().Perm())
+	assert.True(t, mtime.Equal(info.ModTime()))
+
+	info, err = os.Stat(filepath.Join(dir, "14-bis", "bin", "14-bis"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	assert.True(t, mtime.Equal(info.ModTime()))
+
+	info, err = os.Stat(filepath.Join(dir, "14-bis", "README.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	target, err := os.Readlink(filepath.Join(dir, "14-bis", "14-bis"))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("bin", "14-bis"), target)
+
+	info, err = os.Stat(filepath.Join(dir, "14-bis", "LEIAME.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), info.Size())
 
-	os.Remove(in.Name())
-	os.Remove(dest)
+	_ = os.Chmod(filepath.Join(dir, "14-bis", "bin"), 0755)
+}
+
+func TestUntarDirectoryWithinDir(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+
+	in := tarEntries(t, &tar.Header{Name: "test-untar-dir/", Typeflag: tar.TypeDir, Mode: 0755})
+	_, err := untar(dir, in, extractionLimits{})
+	assert.Nil(t, err, err)
+	assert.DirExists(t, filepath.Join(dir, "test-untar-dir"))
+	assert.NoDirExists(t, filepath.Join(wd, "test-untar-dir"))
+}
+
+func TestUntarRefusedEntries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headers  []*tar.Header
+		expected error
+	}{
+		{
+			"traversal",
+			[]*tar.Header{{Name: "../14-bis", Typeflag: tar.TypeReg, Mode: 0755}},
+			ErrOutsideRoot,
+		},
+		{
+			"directory traversal",
+			[]*tar.Header{{Name: "bin/../../14-bis", Typeflag: tar.TypeDir, Mode: 0755}},
+			ErrOutsideRoot,
+		},
+		{
+			"symlink outside",
+			[]*tar.Header{{Name: "14-bis", Typeflag: tar.TypeSymlink, Linkname: "../../usr/bin/14-bis"}},
+			ErrOutsideRoot,
+		},
+		{
+			"absolute symlink",
+			[]*tar.Header{{Name: "14-bis", Typeflag: tar.TypeSymlink, Linkname: "/usr/bin/14-bis"}},
+			ErrOutsideRoot,
+		},
+		{
+			"hard link outside",
+			[]*tar.Header{{Name: "14-bis", Typeflag: tar.TypeLink, Linkname: "../passwd"}},
+			ErrOutsideRoot,
+		},
+		{
+			"write through symlink",
+			[]*tar.Header{
+				{Name: "14-bis", Typeflag: tar.TypeReg, Mode: 0755},
+				{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "14-bis"},
+				{Name: "link", Typeflag: tar.TypeReg, Mode: 0755},
+			},
+			ErrSymlinkTraversal,
+		},
+		{
+			"write through symlinked directory",
+			[]*tar.Header{
+				{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "bin"},
+				{Name: "lib/14-bis", Typeflag: tar.TypeReg, Mode: 0755},
+			},
+			ErrSymlinkTraversal,
+		},
+		{
+			"fifo",
+			[]*tar.Header{{Name: "fifo", Typeflag: tar.TypeFifo}},
+			ErrSpecialFile,
+		},
+		{
+			"device",
+			[]*tar.Header{{Name: "sda", Typeflag: tar.TypeBlock}},
+			ErrSpecialFile,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := untar(t.TempDir(), tarEntries(t, tc.headers...), extractionLimits{})
+
+			var extractionErr *ExtractionError
+			assert.True(t, errors.As(err, &extractionErr), err)
+			assert.Equal(t, tc.headers[len(tc.headers)-1].Name, extractionErr.Entry)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestUntarLinkTraversal(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "staging")
+	assert.Nil(t, os.Mkdir(dir, 0755))
+
+	victim := filepath.Join(parent, "victim.txt")
+	assert.Nil(t, os.WriteFile(victim, []byte("safe"), 0644))
+
+	in := tarEntries(t,
+		&tar.Header{Name: "d/", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "d/e", Typeflag: tar.TypeSymlink, Linkname: ".."},
+		&tar.Header{Name: "out", Typeflag: tar.TypeSymlink, Linkname: "d/e/.."},
+		&tar.Header{Name: "h", Typeflag: tar.TypeLink, Linkname: "out/victim.txt"},
+		&tar.Header{Name: "h", Typeflag: tar.TypeReg, Mode: 0644, Size: 3},
+	)
+
+	_, err := untar(dir, in, extractionLimits{})
+	var extractionErr *ExtractionError
+	assert.True(t, errors.As(err, &extractionErr), err)
+	assert.Equal(t, "out", extractionErr.Entry)
+	assert.ErrorIs(t, err, ErrSymlinkTraversal)
+
+	data, err := os.ReadFile(victim)
+	assert.Nil(t, err, err)
+	assert.Equal(t, "safe", string(data))
+}
+
+func TestUntarLinkRefusedEntries(t *testing.T) {
+	testCases := []struct {
+		name    string
+		headers []*tar.Header
+	}{
+		{
+			"parent of a missing directory",
+			[]*tar.Header{{Name: "out", Typeflag: tar.TypeSymlink, Linkname: "x/.."}},
+		},
+		{
+			"hard link through symlinked directory",
+			[]*tar.Header{
+				{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "bin/14-bis", Typeflag: tar.TypeReg, Mode: 0755},
+				{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "bin"},
+				{Name: "14-bis", Typeflag: tar.TypeLink, Linkname: "lib/14-bis"},
+			},
+		},
+		{
+			"hard link to symlink",
+			[]*tar.Header{
+				{Name: "bin", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "lib", Typeflag: tar.TypeLink, Linkname: "bin"},
+			},
+		},
+		{
+			"directory replaced by symlink",
+			[]*tar.Header{
+				{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "bin", Typeflag: tar.TypeSymlink, Linkname: "."},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := untar(t.TempDir(), tarEntries(t, tc.headers...), extractionLimits{})
+
+			var extractionErr *ExtractionError
+			assert.True(t, errors.As(err, &extractionErr), err)
+			assert.Equal(t, tc.headers[len(tc.headers)-1].Name, extractionErr.Entry)
+			assert.ErrorIs(t, err, ErrSymlinkTraversal)
+		})
+	}
+}
+
+func TestUntarLinks(t *testing.T) {
+	dir := t.TempDir()
+
+	in := tarEntries(t,
+		&tar.Header{Name: "lib/", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "lib/libfoo.so.1.2", Typeflag: tar.TypeReg, Mode: 0644, Size: 3},
+		&tar.Header{Name: "lib/libfoo.so.1", Typeflag: tar.TypeSymlink, Linkname: "libfoo.so.1.2"},
+		&tar.Header{Name: "lib/libfoo.so", Typeflag: tar.TypeSymlink, Linkname: "libfoo.so.1"},
+		&tar.Header{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "bin/libfoo.so", Typeflag: tar.TypeSymlink, Linkname: "../lib/libfoo.so"},
+		&tar.Header{Name: "README.md", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+		&tar.Header{Name: "LEIAME.md", Typeflag: tar.TypeLink, Linkname: "README.md"},
+		&tar.Header{Name: "README.md", Typeflag: tar.TypeReg, Mode: 0644, Size: 2},
+	)
+
+	actual, err := untar(dir, in, extractionLimits{})
+	assert.Nil(t, err, err)
+	assert.Equal(t, 9, actual)
+
+	info, err := os.Stat(filepath.Join(dir, "bin", "libfoo.so"))
+	assert.Nil(t, err, err)
+	assert.Equal(t, int64(3), info.Size())
+
+	// Rewriting a file doesn't write through a hard link to it.
+	info, err = os.Stat(filepath.Join(dir, "LEIAME.md"))
+	assert.Nil(t, err, err)
+	assert.Equal(t, int64(1), info.Size())
+}
+
+func TestUntarLimits(t *testing.T) {
+	headers := []*tar.Header{
+		{Name: "14-bis", Typeflag: tar.TypeReg, Mode: 0755, Size: 600},
+		{Name: "README.md", Typeflag: tar.TypeReg, Mode: 0644, Size: 600},
+	}
+
+	_, err := untar(t.TempDir(), tarEntries(t, headers...), extractionLimits{maxSize: 1000})
+	assert.Equal(t, "README.md: archive exceeds the maximum extracted size", err.Error())
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = untar(t.TempDir(), tarEntries(t, headers...), extractionLimits{maxFiles: 1})
+	assert.Equal(t, "README.md: archive exceeds the maximum number of files", err.Error())
+	assert.ErrorIs(t, err, ErrTooManyFiles)
+
+	actual, err := untar(t.TempDir(), tarEntries(t, headers...), extractionLimits{maxSize: 1200, maxFiles: 2})
+	assert.Nil(t, err, err)
+	assert.Equal(t, 2, actual)
+}
+
+func TestUntarUnknownType(t *testing.T) {
+	_, err := untar(t.TempDir(), tarEntries(t, &tar.Header{Name: "14-bis", Typeflag: 'Z'}), extractionLimits{})
+	assert.Equal(t, "14-bis has unknown type (90?)", err.Error())
+}
+
+func TestUnzipRefusedEntries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		entries  []*zip.FileHeader
+		expected error
+	}{
+		{"traversal", []*zip.FileHeader{{Name: "../14-bis"}}, ErrOutsideRoot},
+		{"absolute traversal", []*zip.FileHeader{{Name: "/../../14-bis"}}, ErrOutsideRoot},
+		{"symlink outside", []*zip.FileHeader{symlinkZipHeader("14-bis")}, ErrOutsideRoot},
+		{
+			"write through symlink",
+			[]*zip.FileHeader{{Name: "bin/"}, symlinkZipHeader("lib"), {Name: "lib/14-bis"}},
+			ErrSymlinkTraversal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "14-bis.zip")
+			writeZipEntries(t, src, tc.entries)
+
+			_, err := unzip(src, extractionLimits{})
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestUnzipLimits(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "14-bis.zip")
+	writeZipEntries(t, src, []*zip.FileHeader{{Name: "bin/"}, {Name: "bin/14-bis"}, {Name: "README.md"}})
+
+	_, err := unzip(src, extractionLimits{maxSize: 10})
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = unzip(src, extractionLimits{maxFiles: 2})
+	assert.ErrorIs(t, err, ErrTooManyFiles)
+
+	actual, err := unzip(src, extractionLimits{})
+	assert.Nil(t, err, err)
+	assert.Equal(t, 3, actual)
+	assert.FileExists(t, filepath.Join(dir, "bin", "14-bis"))
+}
+
+// symlinkZipHeader returns the header of a symbolic link entry, whose content is
+// the link target, which writeZipEntries sets to ../outside or, for lib, to bin.
+func symlinkZipHeader(name string) *zip.FileHeader {
+	header := &zip.FileHeader{Name: name}
+	header.SetMode(fs.ModeSymlink | 0777)
+
+	return header
+}
+
+func writeZipEntries(t *testing.T, dest string, headers []*zip.FileHeader) {
+	file, err := os.Create(dest)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	wr := zip.NewWriter(file)
+	for _, header := range headers {
+		if header.Mode() == 0 && !strings.HasSuffix(header.Name, "/") {
+			header.SetMode(0755)
+		}
+
+		w, err := wr.CreateHeader(header)
+		assert.Nil(t, err)
+
+		switch {
+		case header.Mode()&fs.ModeSymlink != 0 && header.Name == "lib":
+			_, err = w.Write([]byte("bin"))
+		case header.Mode()&fs.ModeSymlink != 0:
+			_, err = w.Write([]byte("../outside"))
+		case !strings.HasSuffix(header.Name, "/"):
+			_, err = w.Write([]byte("#!/bin/sh\n"))
+		}
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, wr.Close())
 }
 
 func createZipFile(dest string) error {
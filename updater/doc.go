@@ -20,8 +20,16 @@ It returns the last release available or raises an error if the current version
 			ProjectPath: "gitlab-org/gitlab",
 		},
 		"0.1.0",
+		false,
 	)
 
+The optional settings, gathered in Options, are given through FindUpdateWithOptions and
+UpdateReleaseWithOptions, which take them as an extra parameter.
+
+Releases are compared according to Semantic Versioning 2.0.0 unless another version scheme
+is set on the provider or through Options.Scheme, e.g. provider.CalVer{} for tags such as
+2024.06.1 or provider.Monotonic{} for build numbers such as r123.
+
 # Update
 
 Updates running program to the last available release.
@@ -34,8 +42,60 @@ It returns the release used to update this program or raises an error if it's al
 			Ssl:         true,
 			ProjectPath: "gitlab-org/gitlab",
 		},
-		"oalienista",
 		"0.1.0",
+		false,
 	)
+
+# Release assets
+
+The file to be installed is picked from the release assets. When the release
+carries the artifacts.json file written by goreleaser, the archive built for the
+running platform (goos, goarch and goarm) is taken from it, together with its
+checksum. Otherwise, the first archive whose name contains the operating system is used.
+
+Archives may be zip files, tar files compressed with gzip, xz, zstd or bzip2, plain tar
+files or single compressed files, e.g. 14-bis.gz. Their format is detected from their
+magic bytes, so mislabeled archives are extracted too.
+
+Checksums are read from the digest of the asset, from a sidecar file such as
+14-bis.tar.gz.sha256 or from the checksums file of the release, named after
+Options.ChecksumsFile (checksums.txt by default). GNU coreutils (sha256sum and the like)
+and BSD (SHA256 (14-bis.tar.gz) = hash) formats are supported, with SHA-256, SHA-512,
+BLAKE2b and BLAKE3 checksums. Options.ChecksumAlgorithm tells the algorithm when the
+format doesn't.
+
+Verification fails closed: a release file that is not listed in the checksums is
+refused with ErrChecksumNotListed, before it is even downloaded, and a digest that
+doesn't match is refused with ErrChecksumMismatch. Both are reported as a ChecksumError
+carrying the expected and actual digests. Options.OnVerification is given a
+VerificationReport of every verification, e.g. to log it for auditing.
+
+The release file is hashed while it is downloaded and verified against its checksum
+before anything is extracted from it, so unverified archives are never unpacked and the
+file is not read back just to be hashed.
+
+Archives are extracted safely, whatever their format: entries must stay within the
+staging directory and must not be written through symbolic links, device and FIFO
+entries are refused, and the extracted content is bounded by Options.MaxExtractedSize
+and Options.MaxExtractedFiles. Refused entries are reported as an ExtractionError,
+which wraps ErrOutsideRoot, ErrSymlinkTraversal, ErrSpecialFile, ErrTooLarge or
+ErrTooManyFiles.
+
+When there is no archive, the raw binary (goreleaser format: binary) is taken instead:
+a release file with no extension or with the .exe one. Release files built for the
+running architecture are preferred. It is checksummed and installed in place of the running executable, with the executable
+bit set.
+
+When Options.SmokeTest is set, the new executable, that is the extracted file named
+after the running one or the raw binary, is run with its arguments before being
+installed. The installation is aborted with ErrSmokeTestFailed if it doesn't exit
+with status 0 within the timeout or, with ExpectVersion, if its output doesn't
+contain the version of the release. It is aborted as well when the archive has no
+file named after the running executable at its root, e.g. when it is wrapped in a
+directory, as nothing would replace the running executable.
+
+Options.RewriteURL is applied to every asset URL right before it is downloaded,
+which lets downloads go through a mirror or a proxy repository while the
+provider API is still queried directly.
 */
 package updater
@@ -2,14 +2,18 @@ package updater
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/aureliano/caravela/provider"
 )
@@ -17,40 +21,175 @@ import (
 const downloadTimeout = time.Second * 120
 const checksumsFileName = "checksums.txt"
 
+// platformArches are the names an architecture goes by in release file names. The
+// most specific come first, so that arm64 is not taken for arm.
+var platformArches = []struct {
+	arch    string
+	aliases []string
+}{
+	{"amd64", []string{"amd64", "x86_64", "x86-64", "x64"}},
+	{"arm64", []string{"arm64", "aarch64"}},
+	{"386", []string{"386", "i686", "x86"}},
+	{"arm", []string{"arm"}},
+}
+
 var mpDownloadFile = downloadFile
 
-func downloadTo(client provider.HTTPClientPlugin, release *provider.Release, dir string) (string, string, error) {
-	fname, furl := findReleaseFileURL(runtime.GOOS, release)
-	if fname == "" {
-		return "", "", fmt.Errorf("there is no version compatible with %s", runtime.GOOS)
-	}
+// releaseDownload is the release file downloaded to be installed.
+type releaseDownload struct {
+	// path is where the release file has been downloaded to.
+	path string
+	// digest is the hex encoded hash of the release file, computed while downloading
+	// with the algorithm of its checksum.
+	digest    string
+	algorithm ChecksumAlgorithm
+	// checksums is the path of the checksums file, empty when the release file is signed.
+	checksums string
+	// source tells where the checksum comes from: the URL of the checksums file or
+	// the asset digest.
+	source string
+}
 
-	fileBin := filepath.Join(dir, fname)
+// downloadTo downloads the release file compatible with the running platform into dir,
+// along with what is needed to check its integrity. When the file is signed and
+// verifier is able to check it, no checksums file is required and its path is empty.
+// Every asset URL goes through opts.RewriteURL, if set, before being downloaded.
+func downloadTo(
+	client provider.HTTPClientPlugin,
+	release *provider.Release,
+	dir string,
+	verifier provider.AssetVerifier,
+	opts Options,
+) (releaseDownload, error) {
+	asset, err := findReleaseAsset(client, release, dir, opts.RewriteURL)
+	if err != nil {
+		return releaseDownload{}, err
+	} else if asset.Name == "" {
+		return releaseDownload{}, fmt.Errorf("there is no version compatible with %s", runtime.GOOS)
+	}
 
-	err := mpDownloadFile(client, furl, fileBin)
+	// The checksum comes first, so the release file is hashed with its algorithm
+	// while it is downloaded, and is not downloaded at all when it is not listed.
+	download, err := downloadChecksums(client, release, asset, filepath.Join(dir, checksumsFileName), opts)
 	if err != nil {
-		return "", "", err
+		return releaseDownload{}, err
 	}
 
-	furl = findChecksumsFileURL(release)
-	fileChecksums := filepath.Join(dir, checksumsFileName)
-	if furl == "" {
-		return "", "", fmt.Errorf("file %s not found", checksumsFileName)
+	signed := verifier != nil && asset.Signature != ""
+	if download.checksums == "" && !signed {
+		return releaseDownload{}, fmt.Errorf("file %s not found", checksumsFile(opts))
 	}
 
-	err = mpDownloadFile(client, furl, fileChecksums)
+	download.path = filepath.Join(dir, asset.Name)
+	furl := rewriteURL(opts.RewriteURL, asset.URL)
+	download.digest, err = mpDownloadFile(client, furl, download.path, download.algorithm)
 	if err != nil {
-		return "", "", err
+		return releaseDownload{}, err
+	}
+
+	if signed {
+		err = verifier.VerifyAsset(asset, download.path)
+		if err != nil {
+			return releaseDownload{}, err
+		}
+	}
+
+	return download, nil
+}
+
+// downloadChecksums writes the checksum of asset into dest and tells where it comes
+// from and its algorithm. The digest of the asset is taken first, then a sidecar file,
+// e.g. 14-bis.tar.gz.sha256, and then the checksums file of the release. When none of
+// them tells the algorithm, opts.ChecksumAlgorithm is taken, if set, or SHA256. The
+// checksums path is empty when the release has no checksum for asset, and a
+// ChecksumError wrapping ErrChecksumNotListed is returned when asset is not listed.
+func downloadChecksums(
+	client provider.HTTPClientPlugin,
+	release *provider.Release,
+	asset provider.Asset,
+	dest string,
+	opts Options,
+) (releaseDownload, error) {
+	var download releaseDownload
+
+	if asset.Digest != "" {
+		if err := writeDigestChecksums(asset.Digest, asset.Name, dest); err != nil {
+			return releaseDownload{}, err
+		}
+		download.source = "asset digest"
+	} else {
+		furl := findChecksumsFileURL(release, checksumsFile(opts))
+		if sidecar := findSidecarAsset(release, asset.Name); sidecar.Name != "" {
+			furl = sidecar.URL
+			download.algorithm = sidecarAlgorithm(sidecar.Name)
+		} else if furl == "" {
+			return releaseDownload{}, nil
+		}
+
+		furl = rewriteURL(opts.RewriteURL, furl)
+		if _, err := mpDownloadFile(client, furl, dest, ""); err != nil {
+			return releaseDownload{}, err
+		}
+		download.source = furl
+	}
+
+	listed, _, err := readChecksum(asset.Name, dest)
+	download.algorithm = firstAlgorithm(listed, download.algorithm, opts.ChecksumAlgorithm, SHA256)
+	if errors.Is(err, ErrChecksumNotListed) {
+		// The asset is not downloaded, so there is no digest to report.
+		report := VerificationReport{File: asset.Name, Source: download.source, Algorithm: download.algorithm}
+		return releaseDownload{}, &ChecksumError{Report: report, Err: ErrChecksumNotListed}
+	} else if err != nil {
+		return releaseDownload{}, err
 	}
 
-	return fileBin, fileChecksums, nil
+	download.checksums = dest
+	return download, nil
 }
 
-func downloadFile(client provider.HTTPClientPlugin, sourceURL, dest string) error {
+func firstAlgorithm(algorithms ...ChecksumAlgorithm) ChecksumAlgorithm {
+	for _, alg := range algorithms {
+		if alg != "" {
+			return alg
+		}
+	}
+
+	return ""
+}
+
+// findReleaseAsset picks the release file to be installed. The metadata in
+// artifacts.json is preferred when the release has it; otherwise the file is
+// chosen by its name.
+func findReleaseAsset(
+	client provider.HTTPClientPlugin,
+	release *provider.Release,
+	dir string,
+	rewrite func(string) string,
+) (provider.Asset, error) {
+	if furl := findAssetURL(release, artifactsFileName); furl != "" {
+		return findReleaseFileFromArtifacts(client, release, rewriteURL(rewrite, furl), dir)
+	}
+
+	return findReleaseFile(runtime.GOOS, release), nil
+}
+
+// downloadFile downloads sourceURL into dest and returns the hex encoded hash of the
+// content, computed with algorithm (SHA256 when empty). The content is hashed as it
+// is written, so it is never read back.
+func downloadFile(
+	client provider.HTTPClientPlugin,
+	sourceURL, dest string,
+	algorithm ChecksumAlgorithm,
+) (string, error) {
+	hasher, err := newChecksumHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
 	file, err := os.Create(dest)
 	if err != nil {
 		os.Remove(dest)
-		return err
+		return "", err
 	}
 	defer file.Close()
 
@@ -60,41 +199,161 @@ func downloadFile(client provider.HTTPClientPlugin, sourceURL, dest string) erro
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http error (%d)", resp.StatusCode)
+		return "", fmt.Errorf("http error (%d)", resp.StatusCode)
 	}
 
-	_, err = io.Copy(file, resp.Body)
+	_, err = io.Copy(file, io.TeeReader(resp.Body, hasher))
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// findReleaseFile picks the archive built for osys or, if there is none, the raw
+// binary, as published by goreleaser with format: binary.
+func findReleaseFile(osys string, release *provider.Release) provider.Asset {
+	if asset := findPlatformAsset(osys, release, isArchive); asset.Name != "" {
+		return asset
 	}
 
-	return nil
+	return findPlatformAsset(osys, release, isRawBinary)
 }
 
-func findReleaseFileURL(osys string, release *provider.Release) (string, string) {
+// findPlatformAsset returns the acceptable asset built for osys. Among the assets
+// only telling the platform by their name, the one built for the running architecture
+// is preferred to one naming no architecture, which is preferred to any other.
+func findPlatformAsset(osys string, release *provider.Release, acceptable func(string) bool) provider.Asset {
+	var found provider.Asset
+	rank := -1
 	for _, asset := range release.Assets {
 		name := strings.ToLower(asset.Name)
-		acceptableType := (strings.HasSuffix(name, ".zip") ||
-			strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"))
-		if strings.Contains(name, osys) && acceptableType {
-			return asset.Name, asset.URL
+		if !acceptable(name) {
+			continue
+		}
+
+		if asset.OS != "" {
+			if asset.OS == osys && (asset.Arch == "" || asset.Arch == runtime.GOARCH) {
+				return asset
+			}
+		} else if strings.Contains(name, osys) {
+			if r := archRank(name); r > rank {
+				found, rank = asset, r
+			}
+		}
+	}
+
+	return found
+}
+
+// archRank ranks the release file named name by the architecture it is built for:
+// 2 for the running one, 1 for none and 0 for any other.
+func archRank(name string) int {
+	for _, p := range platformArches {
+		for _, alias := range p.aliases {
+			if !strings.Contains(name, alias) {
+				continue
+			} else if p.arch == runtime.GOARCH {
+				return 2
+			}
+			return 0
+		}
+	}
+
+	return 1
+}
+
+// isRawBinary tells whether the release file named name is the executable itself,
+// that is, a file with no extension or with the .exe one. A dot followed by a
+// version or a platform, e.g. 14-bis_0.1.10_linux_amd64, doesn't start an extension.
+func isRawBinary(name string) bool {
+	if name == "" || isArchive(name) {
+		return false
+	}
+
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".exe" || !isExtension(ext)
+}
+
+// isExtension tells whether ext, as returned by path.Ext, is a file extension rather
+// than part of a version: it has letters and nothing but letters and digits.
+func isExtension(ext string) bool {
+	letters := false
+	for _, r := range strings.TrimPrefix(ext, ".") {
+		switch {
+		case unicode.IsLetter(r):
+			letters = true
+		case !unicode.IsDigit(r):
+			return false
+		}
+	}
+
+	return letters
+}
+
+// findChecksumsFileURL returns the URL of the checksums file named after pattern,
+// e.g. checksums.txt or *_checksums.txt.
+func findChecksumsFileURL(release *provider.Release, pattern string) string {
+	for _, asset := range release.Assets {
+		if matched, _ := path.Match(pattern, asset.Name); matched {
+			return asset.URL
 		}
 	}
 
-	return "", ""
+	return ""
+}
+
+// findSidecarAsset returns the checksum file published along with the file named
+// name, e.g. 14-bis.tar.gz.sha256.
+func findSidecarAsset(release *provider.Release, name string) provider.Asset {
+	for _, alg := range checksumAlgorithms {
+		for _, suffix := range alg.suffixes {
+			if asset := findAsset(release, name+suffix); asset.Name != "" {
+				return asset
+			}
+		}
+	}
+
+	return provider.Asset{}
+}
+
+func checksumsFile(opts Options) string {
+	if opts.ChecksumsFile == "" {
+		return checksumsFileName
+	}
+
+	return opts.ChecksumsFile
 }
 
-func findChecksumsFileURL(release *provider.Release) string {
+func findAssetURL(release *provider.Release, name string) string {
 	for _, asset := range release.Assets {
-		if asset.Name == checksumsFileName {
+		if asset.Name == name {
 			return asset.URL
 		}
 	}
 
 	return ""
 }
+
+func findAsset(release *provider.Release, name string) provider.Asset {
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			return asset
+		}
+	}
+
+	return provider.Asset{}
+}
+
+func rewriteURL(rewrite func(string) string, url string) string {
+	if rewrite == nil {
+		return url
+	}
+
+	return rewrite(url)
+}
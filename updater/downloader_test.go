@@ -30,22 +30,25 @@ func (plugin *mockHTTPPlugin) Do(req *http.Request) (*http.Response, error) {
 	return res, args.Error(1)
 }
 
+type stubVerifier struct{ err error }
+
+func (v stubVerifier) VerifyAsset(asset provider.Asset, path string) error {
+	return v.err
+}
+
 func TestDownloadToBinNotFound(t *testing.T) {
 	m := new(mockHTTPPlugin)
 	m.On("Do", mock.Anything).Return(nil, nil)
 
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Xpto_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Lorem_x86_64.zip", URL: "http://file-windows.zip"},
 		{Name: "14-bis_Ipsum_x86_64.tar.gz", URL: "http://file-darwin.tar.gz"},
 		{Name: "checksums.txt", URL: "http://checksums.txt"},
 	}
 
-	_, _, err := downloadTo(m, release, "")
+	_, err := downloadTo(m, release, "", nil, Options{})
 	assert.Contains(t, err.Error(), "there is no version compatible with")
 	m.AssertNotCalled(t, "Do", mock.Anything)
 }
@@ -58,18 +61,15 @@ func TestDownloadToChecksumsNotFound(t *testing.T) {
 	}, nil)
 
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip"},
 		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "http://file-darwin.tar.gz"},
 	}
 
-	_, _, err := downloadTo(m, release, os.TempDir())
+	_, err := downloadTo(m, release, os.TempDir(), nil, Options{})
 	assert.Contains(t, err.Error(), "file checksums.txt not found")
-	m.AssertCalled(t, "Do", mock.Anything)
+	m.AssertNotCalled(t, "Do", mock.Anything)
 }
 
 func TestDownloadDownloadBinError(t *testing.T) {
@@ -81,25 +81,23 @@ func TestDownloadDownloadBinError(t *testing.T) {
 		}, nil)
 
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip"},
 		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "http://file-darwin.tar.gz"},
 		{Name: "checksums.txt", URL: "http://checksums.txt"},
 	}
 
-	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceUrl, dest string) error {
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceUrl, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
 		if strings.Contains(dest, "14-bis_") {
-			return fmt.Errorf("failed to download binary")
+			return "", fmt.Errorf("failed to download binary")
 		}
 
-		return nil
+		return "", writeTestChecksums(dest)
 	}
 
-	_, _, err := downloadTo(m, release, os.TempDir())
+	_, err := downloadTo(m, release, os.TempDir(), nil, Options{})
 	assert.Equal(t, "failed to download binary", err.Error())
 }
 
@@ -112,30 +110,33 @@ func TestDownloadDownloadChecksumsError(t *testing.T) {
 		}, nil)
 
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip"},
 		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "http://file-darwin.tar.gz"},
 		{Name: "checksums.txt", URL: "http://checksums.txt"},
 	}
 
-	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceUrl, dest string) error {
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceUrl, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
 		if strings.Contains(dest, "checksums.txt") {
-			return fmt.Errorf("failed to download checksums")
+			return "", fmt.Errorf("failed to download checksums")
 		}
 
-		return nil
+		return "", nil
 	}
 
-	_, _, err := downloadTo(m, release, os.TempDir())
+	_, err := downloadTo(m, release, os.TempDir(), nil, Options{})
 	assert.Equal(t, "failed to download checksums", err.Error())
 }
 
 func TestDownloadRelease(t *testing.T) {
 	m := new(mockHTTPPlugin)
+	m.On("Do", mock.MatchedBy(func(req *http.Request) bool { return req.URL.String() == "http://checksums.txt" })).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte("SHA512 (14-bis_Linux_x86_64.tar.gz) = 3627"))),
+		}, nil)
 	m.On("Do", mock.Anything).Return(
 		&http.Response{
 			StatusCode: http.StatusOK,
@@ -143,10 +144,7 @@ func TestDownloadRelease(t *testing.T) {
 		}, nil)
 
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip"},
 		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "http://file-darwin.tar.gz"},
@@ -164,21 +162,295 @@ func TestDownloadRelease(t *testing.T) {
 	mpDownloadFile = downloadFile
 
 	dir := os.TempDir()
-	abin, achecksum, err := downloadTo(m, release, dir)
+	download, err := downloadTo(m, release, dir, nil, Options{})
 	ebin, echecksum := filepath.Join(dir, fmt.Sprintf(
 		"14-bis_%s_x86_64.%s", osName, suffix)), filepath.Join(dir, "checksums.txt")
 
 	assert.Nil(t, err, err)
-	assert.Equal(t, ebin, abin)
-	assert.Equal(t, echecksum, achecksum)
+	assert.Equal(t, ebin, download.path)
+	assert.Equal(t, echecksum, download.checksums)
+	if osName == "Linux" {
+		// sha512 of "12345", the content served by the mock, as told by the checksums file.
+		assert.Equal(t, "3627909a29c31381a071ec27f7c9ca97726182aed29a7ddd2e54353322cfb30a"+
+			"bb9e3a6df2ac2c20fe23436311d678564d0c8d305930575f60e2d3d048184d79", download.digest)
+	}
 	m.AssertCalled(t, "Do", mock.Anything)
 }
 
+func TestDownloadReleaseAssetDigest(t *testing.T) {
+	m := new(mockHTTPPlugin)
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz", Digest: "sha256:123"},
+		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip", Digest: "sha256:456"},
+		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "http://file-darwin.tar.gz", Digest: "sha256:789"},
+	}
+
+	var urls []string
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		urls = append(urls, sourceURL)
+		return "", nil
+	}
+
+	dir, _ := os.MkdirTemp("", "test-download-digest-*")
+	defer os.RemoveAll(dir)
+
+	download, err := downloadTo(m, release, dir, nil, Options{})
+	assert.Nil(t, err, err)
+	assert.Len(t, urls, 1)
+
+	data, err := os.ReadFile(download.checksums)
+	assert.Nil(t, err, err)
+
+	name := filepath.Base(download.path)
+	digest := findAsset(release, name).Digest[len("sha256:"):]
+	assert.Equal(t, fmt.Sprintf("SHA256 (%s) = %s\n", name, digest), string(data))
+	mpDownloadFile = downloadFile
+}
+
+func TestDownloadReleaseRewriteURL(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "https://github.com/santos/14-bis/file-linux.tar.gz"},
+		{Name: "14-bis_Windows_x86_64.zip", URL: "https://github.com/santos/14-bis/file-windows.zip"},
+		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "https://github.com/santos/14-bis/file-darwin.tar.gz"},
+		{Name: "checksums.txt", URL: "https://github.com/santos/14-bis/checksums.txt"},
+	}
+
+	var urls []string
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		urls = append(urls, sourceURL)
+		return "", writeTestChecksums(dest)
+	}
+	defer func() { mpDownloadFile = downloadFile }()
+
+	rewrite := provider.PrefixRewriter(map[string]string{
+		"https://github.com/": "https://artifactory.corp.com/github/",
+	})
+	_, err := downloadTo(nil, release, os.TempDir(), nil, Options{RewriteURL: rewrite})
+
+	assert.Nil(t, err, err)
+	assert.Len(t, urls, 2)
+	for _, url := range urls {
+		assert.True(t, strings.HasPrefix(url, "https://artifactory.corp.com/github/santos/14-bis/"), url)
+	}
+	assert.Equal(t, "https://artifactory.corp.com/github/santos/14-bis/checksums.txt", urls[0])
+}
+
+func TestDownloadReleaseSidecarChecksum(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis.zip", URL: "http://file.zip", OS: runtime.GOOS},
+		{Name: "14-bis.zip.b2", URL: "http://file.zip.b2"},
+		{Name: "checksums.txt", URL: "http://checksums.txt"},
+	}
+
+	var urls []string
+	var algorithms []ChecksumAlgorithm
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		urls = append(urls, sourceURL)
+		algorithms = append(algorithms, algorithm)
+		return "", os.WriteFile(dest, []byte("abc123\n"), 0600)
+	}
+	defer func() { mpDownloadFile = downloadFile }()
+
+	dir, _ := os.MkdirTemp("", "test-download-sidecar-*")
+	defer os.RemoveAll(dir)
+
+	download, err := downloadTo(nil, release, dir, nil, Options{ChecksumAlgorithm: SHA512})
+	assert.Nil(t, err, err)
+	assert.Equal(t, filepath.Join(dir, "checksums.txt"), download.checksums)
+	assert.Equal(t, []string{"http://file.zip.b2", "http://file.zip"}, urls)
+	assert.Equal(t, BLAKE2b, algorithms[1])
+}
+
+func TestDownloadReleaseChecksumsFileSettings(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis.zip", URL: "http://file.zip", OS: runtime.GOOS},
+		{Name: "14-bis_1.0.0_checksums.txt", URL: "http://14-bis_1.0.0_checksums.txt"},
+	}
+
+	var urls []string
+	var algorithms []ChecksumAlgorithm
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		urls = append(urls, sourceURL)
+		algorithms = append(algorithms, algorithm)
+		return "", os.WriteFile(dest, []byte("abc123  14-bis.zip\n"), 0600)
+	}
+	defer func() { mpDownloadFile = downloadFile }()
+
+	dir, _ := os.MkdirTemp("", "test-download-checksums-*")
+	defer os.RemoveAll(dir)
+
+	_, err := downloadTo(nil, release, dir, nil, Options{})
+	assert.Equal(t, "file checksums.txt not found", err.Error())
+
+	opts := Options{ChecksumsFile: "*_checksums.txt", ChecksumAlgorithm: SHA512}
+	_, err = downloadTo(nil, release, dir, nil, opts)
+	assert.Nil(t, err, err)
+	assert.Equal(t, []string{"http://14-bis_1.0.0_checksums.txt", "http://file.zip"}, urls)
+	assert.Equal(t, SHA512, algorithms[1])
+}
+
+func TestDownloadReleaseNotListed(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis.zip", URL: "http://file.zip", OS: runtime.GOOS},
+		{Name: "checksums.txt", URL: "http://checksums.txt"},
+	}
+
+	var urls []string
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		urls = append(urls, sourceURL)
+		return "", writeTestChecksums(dest)
+	}
+	defer func() { mpDownloadFile = downloadFile }()
+
+	dir, _ := os.MkdirTemp("", "test-download-not-listed-*")
+	defer os.RemoveAll(dir)
+
+	_, err := downloadTo(nil, release, dir, nil, Options{})
+	assert.ErrorIs(t, err, ErrChecksumNotListed)
+	assert.Equal(t, []string{"http://checksums.txt"}, urls)
+
+	var checksumErr *ChecksumError
+	assert.ErrorAs(t, err, &checksumErr)
+	expected := VerificationReport{File: "14-bis.zip", Source: "http://checksums.txt", Algorithm: SHA256}
+	assert.Equal(t, expected, checksumErr.Report)
+	assert.Equal(t, "14-bis.zip: not listed in the checksums", err.Error())
+}
+
+func TestDownloadReleaseSignedAsset(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis.zip", URL: "http://file.zip", OS: runtime.GOOS, Signature: "c2lnbmF0dXJl"},
+	}
+
+	mpDownloadFile = func(client provider.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		return "", nil
+	}
+	defer func() { mpDownloadFile = downloadFile }()
+
+	_, err := downloadTo(nil, release, os.TempDir(), nil, Options{})
+	assert.Equal(t, "file checksums.txt not found", err.Error())
+
+	_, err = downloadTo(nil, release, os.TempDir(), stubVerifier{err: fmt.Errorf("bad signature")}, Options{})
+	assert.Equal(t, "bad signature", err.Error())
+
+	download, err := downloadTo(nil, release, os.TempDir(), stubVerifier{}, Options{})
+	assert.Nil(t, err, err)
+	assert.Equal(t, filepath.Join(os.TempDir(), "14-bis.zip"), download.path)
+	assert.Equal(t, "", download.checksums)
+}
+
+func TestFindAsset(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz", Digest: "sha256:123"},
+		{Name: "checksums.txt", URL: "http://checksums.txt"},
+	}
+
+	assert.Equal(t, "sha256:123", findAsset(release, "14-bis_Linux_x86_64.tar.gz").Digest)
+	assert.Equal(t, "http://checksums.txt", findAsset(release, "checksums.txt").URL)
+	assert.Equal(t, provider.Asset{}, findAsset(release, "unknown"))
+}
+
+func TestFetchReleaseFileUrlPlatformTagged(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis.zip", URL: "http://file-other-arch.zip", OS: "linux", Arch: "unknown"},
+		{Name: "14-bis.zip", URL: "http://file-mac.zip", OS: "darwin"},
+		{Name: "14-bis.zip", URL: "http://file-linux.zip", OS: "linux", Arch: runtime.GOARCH},
+	}
+
+	asset := findReleaseFile("linux", release)
+	assert.Equal(t, "14-bis.zip", asset.Name)
+	assert.Equal(t, "http://file-linux.zip", asset.URL)
+
+	asset = findReleaseFile("darwin", release)
+	assert.Equal(t, "14-bis.zip", asset.Name)
+	assert.Equal(t, "http://file-mac.zip", asset.URL)
+
+	asset = findReleaseFile("windows", release)
+	assert.Equal(t, "", asset.Name)
+}
+
+func TestFetchReleaseFileUrlRawBinary(t *testing.T) {
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "checksums.txt", URL: "http://checksums.txt"},
+		{Name: "14-bis_linux_amd64.sbom", URL: "http://file-linux.sbom"},
+		{Name: "14-bis_linux_amd64", URL: "http://file-linux"},
+		{Name: "14-bis_windows_amd64.exe", URL: "http://file-windows.exe"},
+		{Name: "14-bis_darwin_amd64.tar.gz", URL: "http://file-darwin.tar.gz"},
+		{Name: "14-bis_darwin_amd64", URL: "http://file-darwin"},
+	}
+
+	asset := findReleaseFile("linux", release)
+	assert.Equal(t, "14-bis_linux_amd64", asset.Name)
+	assert.Equal(t, "http://file-linux", asset.URL)
+
+	asset = findReleaseFile("windows", release)
+	assert.Equal(t, "14-bis_windows_amd64.exe", asset.Name)
+	assert.Equal(t, "http://file-windows.exe", asset.URL)
+
+	asset = findReleaseFile("darwin", release)
+	assert.Equal(t, "14-bis_darwin_amd64.tar.gz", asset.Name)
+	assert.Equal(t, "http://file-darwin.tar.gz", asset.URL)
+}
+
+func TestFetchReleaseFileUrlRunningArch(t *testing.T) {
+	other := "arm64"
+	if runtime.GOARCH == other {
+		other = "amd64"
+	}
+
+	release := new(provider.Release)
+	release.Assets = []provider.Asset{
+		{Name: "14-bis_linux_" + other, URL: "http://file-other-arch"},
+		{Name: "14-bis_linux", URL: "http://file-no-arch"},
+		{Name: "14-bis_linux_" + runtime.GOARCH, URL: "http://file-running-arch"},
+	}
+
+	asset := findReleaseFile("linux", release)
+	assert.Equal(t, "http://file-running-arch", asset.URL)
+
+	release.Assets = release.Assets[:2]
+	asset = findReleaseFile("linux", release)
+	assert.Equal(t, "http://file-no-arch", asset.URL)
+
+	release.Assets = release.Assets[:1]
+	asset = findReleaseFile("linux", release)
+	assert.Equal(t, "http://file-other-arch", asset.URL)
+}
+
+func TestIsRawBinary(t *testing.T) {
+	for _, name := range []string{"14-bis", "14-bis_linux_amd64", "14-bis_0.1.10_Linux_x86_64", "14-bis.exe"} {
+		assert.True(t, isRawBinary(name), name)
+	}
+
+	names := []string{
+		"", "14-bis.zip", "14-bis.tar.gz", "14-bis.tgz", "checksums.txt", "artifacts.json",
+		"14-bis.tar.gz.sbom", "14-bis.deb", "14-bis.rpm", "14-bis.sig", "14-bis.pem",
+		"14-bis_linux_amd64.intoto.jsonl", "14-bis_linux_amd64.spdx", "14-bis.pub", "14-bis_linux_amd64.bundle",
+	}
+	for _, name := range names {
+		assert.False(t, isRawBinary(name), name)
+	}
+}
+
 func TestDownloadFileWrongDest(t *testing.T) {
 	m := new(mockHTTPPlugin)
 	m.On("Do", mock.Anything).Return(nil, nil)
 
-	err := downloadFile(m, "http://file-linux.tar.gz", filepath.Join("unknown", "path"))
+	_, err := downloadFile(m, "http://file-linux.tar.gz", filepath.Join("unknown", "path"), "")
 	assert.NotNil(t, err, err)
 	m.AssertNotCalled(t, "Do", mock.Anything)
 }
@@ -188,7 +460,7 @@ func TestDownloadFileHttpError(t *testing.T) {
 	m.On("Do", mock.Anything).Return(nil, fmt.Errorf("some error"))
 
 	dest := filepath.Join(os.TempDir(), "file-linux.tar.gz")
-	actual := downloadFile(m, "http://file-linux.tar.gz", dest)
+	_, actual := downloadFile(m, "http://file-linux.tar.gz", dest, "")
 	expected := "some error"
 
 	assert.Equal(t, expected, actual.Error())
@@ -204,7 +476,7 @@ func TestDownloadFile404(t *testing.T) {
 		}, nil)
 
 	dest := filepath.Join(os.TempDir(), "file-linux.tar.gz")
-	actual := downloadFile(m, "http://file-linux.tar.gz", dest)
+	_, actual := downloadFile(m, "http://file-linux.tar.gz", dest, "")
 	expected := fmt.Errorf("http error (404)")
 
 	assert.Equal(t, expected.Error(), actual.Error())
@@ -220,8 +492,9 @@ func TestDownloadFile(t *testing.T) {
 		}, nil)
 
 	dest := filepath.Join(os.TempDir(), "file-linux.tar.gz")
-	err := downloadFile(m, "http://file-linux.tar.gz", dest)
+	digest, err := downloadFile(m, "http://file-linux.tar.gz", dest, "")
 	assert.Nil(t, err, err)
+	assert.Equal(t, "5994471abb01112afcc18159f6cc74b4f511b99806da59b3caf5a9c173cacfc5", digest)
 	m.AssertCalled(t, "Do", mock.Anything)
 
 	file, err := os.Open(dest)
@@ -239,10 +512,7 @@ func TestDownloadFile(t *testing.T) {
 
 func TestFetchReleaseFileUrl(t *testing.T) {
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Linux_arm64.deb", URL: "http://file-linux.deb"},
 		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip"},
@@ -281,9 +551,9 @@ func TestFetchReleaseFileUrl(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			name, url := findReleaseFileURL(tc.input, release)
-			if name != tc.expected[0] && url != tc.expected[1] {
-				t.Errorf("expected [%s,  %s], but got [%s, %s]", tc.expected[0], tc.expected[1], name, url)
+			asset := findReleaseFile(tc.input, release)
+			if asset.Name != tc.expected[0] && asset.URL != tc.expected[1] {
+				t.Errorf("expected [%s,  %s], but got [%s, %s]", tc.expected[0], tc.expected[1], asset.Name, asset.URL)
 			}
 		})
 	}
@@ -291,33 +561,32 @@ func TestFetchReleaseFileUrl(t *testing.T) {
 
 func TestFindChecksumsFileUrlNoCheckSums(t *testing.T) {
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip"},
 		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "http://file-darwin.tar.gz"},
 	}
 
-	actual := findChecksumsFileURL(release)
+	actual := findChecksumsFileURL(release, checksumsFileName)
 	expected := ""
 	assert.Equal(t, expected, actual)
 }
 
 func TestFindChecksumsFileUrl(t *testing.T) {
 	release := new(provider.Release)
-	release.Assets = []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	}{
+	release.Assets = []provider.Asset{
 		{Name: "14-bis_Linux_x86_64.tar.gz", URL: "http://file-linux.tar.gz"},
 		{Name: "14-bis_Windows_x86_64.zip", URL: "http://file-windows.zip"},
 		{Name: "14-bis_Darwin_x86_64.tar.gz", URL: "http://file-darwin.tar.gz"},
 		{Name: "checksums.txt", URL: "http://checksums.txt"},
 	}
 
-	actual := findChecksumsFileURL(release)
+	actual := findChecksumsFileURL(release, checksumsFileName)
 	expected := "http://checksums.txt"
 	assert.Equal(t, expected, actual)
 }
+
+func writeTestChecksums(dest string) error {
+	content := "123  14-bis_Linux_x86_64.tar.gz\n456  14-bis_Windows_x86_64.zip\n789  14-bis_Darwin_x86_64.tar.gz\n"
+	return os.WriteFile(dest, []byte(content), 0600)
+}
@@ -0,0 +1,258 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultMaxExtractedSize = 1 << 30
+const defaultMaxExtractedFiles = 10000
+
+var (
+	// ErrOutsideRoot is reported for an archive entry that would be extracted,
+	// or would link, outside of the extraction directory.
+	ErrOutsideRoot = errors.New("outside of the extraction directory")
+
+	// ErrSymlinkTraversal is reported for an archive entry that would be
+	// written through a symbolic link.
+	ErrSymlinkTraversal = errors.New("written through a symbolic link")
+
+	// ErrSpecialFile is reported for device and FIFO archive entries.
+	ErrSpecialFile = errors.New("device and fifo files are not allowed")
+
+	// ErrTooLarge is reported when the extracted files exceed the maximum size.
+	ErrTooLarge = errors.New("archive exceeds the maximum extracted size")
+
+	// ErrTooManyFiles is reported when an archive exceeds the maximum number of entries.
+	ErrTooManyFiles = errors.New("archive exceeds the maximum number of files")
+)
+
+// ExtractionError reports an archive entry refused on extraction. Err is one of
+// ErrOutsideRoot, ErrSymlinkTraversal, ErrSpecialFile, ErrTooLarge or ErrTooManyFiles.
+type ExtractionError struct {
+	Entry string
+	Err   error
+}
+
+func (e *ExtractionError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Entry, e.Err)
+}
+
+func (e *ExtractionError) Unwrap() error {
+	return e.Err
+}
+
+// extractionLimits bounds the content extracted from an archive. Zero values
+// stand for the default limits.
+type extractionLimits struct {
+	maxSize  int64
+	maxFiles int
+}
+
+// extractor writes the entries of an archive within root, enforcing limits. It is
+// shared by every archive format, so they are all extracted safely.
+type extractor struct {
+	root   string
+	limits extractionLimits
+	size   int64
+	files  int
+}
+
+func newExtractor(root string, limits extractionLimits) *extractor {
+	if limits.maxSize <= 0 {
+		limits.maxSize = defaultMaxExtractedSize
+	}
+
+	if limits.maxFiles <= 0 {
+		limits.maxFiles = defaultMaxExtractedFiles
+	}
+
+	return &extractor{root: root, limits: limits}
+}
+
+// entry accounts for a new archive entry and returns the path it is extracted to,
+// which must be within the root and must not go through a symbolic link.
+func (e *extractor) entry(name string) (string, error) {
+	e.files++
+	if e.files > e.limits.maxFiles {
+		return "", &ExtractionError{Entry: name, Err: ErrTooManyFiles}
+	}
+
+	path := filepath.Join(e.root, filepath.FromSlash(name))
+	if !isWithinDir(e.root, path) {
+		return "", &ExtractionError{Entry: name, Err: ErrOutsideRoot}
+	}
+
+	if e.throughSymlink(path) {
+		return "", &ExtractionError{Entry: name, Err: ErrSymlinkTraversal}
+	}
+
+	return path, nil
+}
+
+// throughSymlink tells whether one of the existing parents of path is a symbolic link.
+func (e *extractor) throughSymlink(path string) bool {
+	rel, err := filepath.Rel(e.root, filepath.Dir(path))
+	if err != nil || rel == "." {
+		return false
+	}
+
+	current := e.root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+
+		info, err := os.Lstat(current)
+		if err != nil {
+			return false
+		} else if info.Mode()&os.ModeSymlink != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *extractor) dir(path string) error {
+	return os.MkdirAll(path, permDir)
+}
+
+// file writes the content of in to path, as long as the total extracted size stays
+// within the limit. An existing symbolic link at path is never written through, and
+// an existing file is unlinked first, so a hard link to it is never written through.
+func (e *extractor) file(name, path string, in io.Reader, mode fs.FileMode) error {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return &ExtractionError{Entry: name, Err: ErrSymlinkTraversal}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), permDir); err != nil {
+		return err
+	}
+
+	if err := removeExisting(path); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	remaining := e.limits.maxSize - e.size
+	n, err := io.CopyN(out, in, remaining+1)
+	e.size += n
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	} else if n > remaining {
+		return &ExtractionError{Entry: name, Err: ErrTooLarge}
+	}
+
+	// The permissions given to OpenFile are subject to the umask.
+	return os.Chmod(path, mode)
+}
+
+// symlink creates a symbolic link at path pointing to target, which must be
+// relative and resolve within the root. An existing directory at path is never
+// replaced, as links already checked may go through it.
+func (e *extractor) symlink(name, path, target string) error {
+	target = filepath.FromSlash(target)
+	if filepath.IsAbs(target) {
+		return &ExtractionError{Entry: name, Err: ErrOutsideRoot}
+	}
+
+	if err := e.checkTarget(name, filepath.Dir(path), target); err != nil {
+		return err
+	}
+
+	if info, err := os.Lstat(path); err == nil && info.IsDir() {
+		return &ExtractionError{Entry: name, Err: ErrSymlinkTraversal}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), permDir); err != nil {
+		return err
+	}
+
+	if err := removeExisting(path); err != nil {
+		return err
+	}
+
+	return os.Symlink(target, path)
+}
+
+// hardlink creates a hard link at path to the entry target of the archive.
+func (e *extractor) hardlink(name, path, target string) error {
+	if err := e.checkTarget(name, e.root, filepath.FromSlash(target)); err != nil {
+		return err
+	}
+
+	// A hard link to a symbolic link would be a symbolic link itself.
+	targetPath := filepath.Join(e.root, filepath.FromSlash(target))
+	if info, err := os.Lstat(targetPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return &ExtractionError{Entry: name, Err: ErrSymlinkTraversal}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), permDir); err != nil {
+		return err
+	}
+
+	if err := removeExisting(path); err != nil {
+		return err
+	}
+
+	return os.Link(targetPath, path)
+}
+
+// checkTarget checks the link target, relative to dir, component by component as
+// the system resolves it: it must stay within the root and must not go through a
+// symbolic link, though it may be one itself, e.g. libfoo.so -> libfoo.so.1. A parent
+// (..) is only taken from an existing directory, since a missing component could later
+// be created as a symbolic link.
+func (e *extractor) checkTarget(name, dir, target string) error {
+	var parts []string
+	for _, part := range strings.Split(target, string(filepath.Separator)) {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+
+	current := dir
+	for i, part := range parts {
+		if part == ".." {
+			if info, err := os.Lstat(current); err != nil || !info.IsDir() {
+				return &ExtractionError{Entry: name, Err: ErrSymlinkTraversal}
+			}
+			current = filepath.Dir(current)
+		} else {
+			current = filepath.Join(current, part)
+		}
+
+		if !isWithinDir(e.root, current) {
+			return &ExtractionError{Entry: name, Err: ErrOutsideRoot}
+		}
+
+		last := i == len(parts)-1
+		if info, err := os.Lstat(current); err == nil && info.Mode()&os.ModeSymlink != 0 && !last {
+			return &ExtractionError{Entry: name, Err: ErrSymlinkTraversal}
+		}
+	}
+
+	return nil
+}
+
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func removeExisting(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractionError(t *testing.T) {
+	var err error = &ExtractionError{Entry: "../14-bis", Err: ErrOutsideRoot}
+
+	assert.Equal(t, "../14-bis: outside of the extraction directory", err.Error())
+	assert.True(t, errors.Is(err, ErrOutsideRoot))
+	assert.False(t, errors.Is(err, ErrTooLarge))
+
+	wrapped := fmt.Errorf("update failed: %w", err)
+	var extractionErr *ExtractionError
+	assert.True(t, errors.As(wrapped, &extractionErr))
+	assert.Equal(t, "../14-bis", extractionErr.Entry)
+}
+
+func TestNewExtractorDefaultLimits(t *testing.T) {
+	e := newExtractor("/tmp/14-bis", extractionLimits{})
+	assert.Equal(t, extractionLimits{maxSize: 1 << 30, maxFiles: 10000}, e.limits)
+
+	e = newExtractor("/tmp/14-bis", extractionLimits{maxSize: 10, maxFiles: 1})
+	assert.Equal(t, extractionLimits{maxSize: 10, maxFiles: 1}, e.limits)
+}
+
+func TestExtractorFile(t *testing.T) {
+	dir := t.TempDir()
+	e := newExtractor(dir, extractionLimits{maxSize: 10})
+
+	path, err := e.entry("bin/14-bis")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "bin", "14-bis"), path)
+
+	err = e.file("bin/14-bis", path, strings.NewReader("0123456789"), 0700)
+	assert.Nil(t, err, err)
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	assert.Equal(t, int64(10), e.size)
+
+	path, _ = e.entry("README.md")
+	err = e.file("README.md", path, strings.NewReader("#"), 0600)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestExtractorFileInvalidDest(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "bin"), []byte("#"), 0600))
+	e := newExtractor(dir, extractionLimits{})
+
+	err := e.file("bin/14-bis", filepath.Join(dir, "bin", "14-bis"), strings.NewReader("#"), 0700)
+	assert.NotNil(t, err)
+}
+
+func TestIsWithinDir(t *testing.T) {
+	dir := filepath.Join("tmp", "14-bis")
+
+	assert.True(t, isWithinDir(dir, dir))
+	assert.True(t, isWithinDir(dir, filepath.Join(dir, "bin", "14-bis")))
+	assert.True(t, isWithinDir(dir, filepath.Join(dir, "..bis")))
+	assert.False(t, isWithinDir(dir, filepath.Join("tmp")))
+	assert.False(t, isWithinDir(dir, filepath.Join("tmp", "14-bis-other")))
+	assert.False(t, isWithinDir(dir, filepath.Join("tmp", "14-bis", "..", "..", "etc")))
+}
@@ -1,6 +1,10 @@
 package updater
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	pvdr "github.com/aureliano/caravela/provider"
 )
 
@@ -14,31 +18,85 @@ func FindUpdate(
 	currver string,
 	ignoreCache bool,
 ) (*pvdr.Release, error) {
-	var release *pvdr.Release
-	var err error
+	return FindUpdateWithOptions(client, provider, currver, ignoreCache, Options{})
+}
+
+// FindUpdateWithOptions is FindUpdate along with the optional settings in opts.
+// It is an error for currver not to follow the version scheme.
+//
+// Releases are compared according to opts.Scheme or, if not set, to the
+// version scheme of the provider. Only releases accepted by opts.Channel
+// are offered, so stable users never receive pre-releases nor drafts, and,
+// when opts.Constraint is set, the highest release satisfying it is taken.
+// Versions in opts.SkipVersions or in the blocklist at opts.BlocklistURL are
+// never offered, the next best release being taken in their place. So are
+// staged releases this installation is not yet within the rollout of, see
+// opts.InstallationID.
+//
+// When opts.TargetVersion is set, that release is looked up instead, whatever
+// the channel, the constraint and the rollout, and it is an error for it to be
+// yanked or to be older than the current version unless opts.AllowDowngrade is set.
+func FindUpdateWithOptions(
+	client pvdr.HTTPClientPlugin,
+	provider pvdr.UpdaterProvider,
+	currver string,
+	ignoreCache bool,
+	opts Options,
+) (*pvdr.Release, error) {
+	if err := validateVersion(provider, opts, currver); err != nil {
+		return nil, err
+	}
 
+	if opts.TargetVersion != "" {
+		return findTargetRelease(client, provider, currver, opts)
+	}
+
+	skipped, err := skippedVersions(client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	accepts, err := releaseFilter(provider, opts, skipped)
+	if err != nil {
+		return nil, err
+	}
+
+	var release *pvdr.Release
 	if ignoreCache {
-		release, err = provider.FetchLastRelease(client)
+		release, err = fetchLastRelease(client, provider, opts, accepts)
 	} else {
-		release, err = findUpdateUseCache(client, provider)
+		release, err = findUpdateUseCache(client, provider, opts, accepts, skipped)
 	}
 
 	if err != nil {
 		return nil, err
+	} else if release == nil {
+		return &pvdr.Release{}, nil
 	}
 
-	if release.CompareTo(&pvdr.Release{Name: currver}) == 1 {
+	scheme := versionScheme(provider, opts)
+	if accepts(release) && release.CompareWith(&pvdr.Release{Name: currver}, scheme) == 1 {
 		return release, nil
 	}
 
 	return &pvdr.Release{}, nil
 }
 
-func findUpdateUseCache(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvider) (*pvdr.Release, error) {
+func findUpdateUseCache(
+	client pvdr.HTTPClientPlugin,
+	provider pvdr.UpdaterProvider,
+	opts Options,
+	accepts func(*pvdr.Release) bool,
+	skipped []string,
+) (*pvdr.Release, error) {
+	key := cacheKey(opts, skipped)
 	release, err := provider.RestoreCacheRelease()
 
-	if err != nil {
-		release, err = provider.FetchLastRelease(client)
+	// A release cached with another filter, e.g. for the stable channel while beta is
+	// asked for, or that is not accepted anymore, e.g. yanked since, is fetched again,
+	// so the best release is offered. An empty one tells that there was no release.
+	if err != nil || (release != nil && (release.CacheKey != key || (release.Name != "" && !accepts(release)))) {
+		release, err = fetchLastRelease(client, provider, opts, accepts)
 		if err != nil {
 			return nil, err
 		}
@@ -47,8 +105,206 @@ func findUpdateUseCache(client pvdr.HTTPClientPlugin, provider pvdr.UpdaterProvi
 			release = &pvdr.Release{}
 		}
 
-		_ = provider.CacheRelease(*release)
+		cached := *release
+		cached.CacheKey = key
+		_ = provider.CacheRelease(cached)
+	} else if release != nil {
+		release.CacheKey = ""
 	}
 
 	return release, nil
 }
+
+// cacheKey identifies the filter a release is picked with: the channel, the version
+// constraint, the skipped versions and the version scheme. It is empty for the default
+// filter, so releases cached before the key existed are still restored.
+func cacheKey(opts Options, skipped []string) string {
+	var parts []string
+	if opts.Channel != pvdr.Stable {
+		parts = append(parts, "channel="+opts.Channel.String())
+	}
+	if opts.Constraint != "" {
+		parts = append(parts, "constraint="+opts.Constraint)
+	}
+	if len(skipped) > 0 {
+		versions := append([]string(nil), skipped...)
+		sort.Strings(versions)
+		parts = append(parts, "skip="+strings.Join(versions, ","))
+	}
+	if opts.Scheme != nil {
+		parts = append(parts, fmt.Sprintf("scheme=%T", opts.Scheme))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// fetchLastRelease queries provider for its last release. When the provider is able
+// to list its releases, the last one is picked from the list if a scheme is given,
+// so it is compared according to that scheme, or if the last release of the provider
+// is not accepted, e.g. a pre-release for a stable user or a release out of the
+// version constraint.
+func fetchLastRelease(
+	client pvdr.HTTPClientPlugin,
+	provider pvdr.UpdaterProvider,
+	opts Options,
+	accepts func(*pvdr.Release) bool,
+) (*pvdr.Release, error) {
+	fetcher, ok := provider.(pvdr.ReleasesFetcher)
+	if !ok {
+		return provider.FetchLastRelease(client)
+	}
+
+	scheme := versionScheme(provider, opts)
+	if opts.Scheme == nil {
+		release, err := provider.FetchLastRelease(client)
+		if err != nil || release == nil || accepts(release) {
+			return release, err
+		}
+	}
+
+	releases, err := fetcher.FetchReleases(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return pvdr.LatestRelease(filterReleases(releases, accepts), scheme), nil
+}
+
+// findTargetRelease looks up the release named after opts.TargetVersion among
+// the releases of provider. Names are compared according to the version scheme,
+// so v1.8.3 is found when 1.8.3 is asked for.
+func findTargetRelease(
+	client pvdr.HTTPClientPlugin,
+	provider pvdr.UpdaterProvider,
+	currver string,
+	opts Options,
+) (*pvdr.Release, error) {
+	fetcher, ok := provider.(pvdr.ReleasesFetcher)
+	if !ok {
+		return nil, fmt.Errorf("provider is not able to look up release %s", opts.TargetVersion)
+	}
+
+	releases, err := fetcher.FetchReleases(client)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := versionScheme(provider, opts)
+	target := &pvdr.Release{Name: opts.TargetVersion}
+
+	var release *pvdr.Release
+	for _, r := range releases {
+		if !r.Draft && validateVersion(provider, opts, r.Name) == nil && r.CompareWith(target, scheme) == 0 {
+			release = r
+			break
+		}
+	}
+
+	if release == nil {
+		return nil, fmt.Errorf("release %s not found", opts.TargetVersion)
+	}
+
+	skipped, err := skippedVersions(client, opts)
+	if err != nil {
+		return nil, err
+	} else if isSkipped(release, skipped, scheme) {
+		return nil, fmt.Errorf("release %s has been yanked", release.Name)
+	}
+
+	switch release.CompareWith(&pvdr.Release{Name: currver}, scheme) {
+	case 0:
+		return &pvdr.Release{}, nil
+	case -1:
+		if !opts.AllowDowngrade {
+			return nil, fmt.Errorf("release %s is older than current version %s and downgrade is not allowed",
+				release.Name, currver)
+		}
+	}
+
+	return release, nil
+}
+
+// releaseFilter returns a function telling whether a release may be offered, that is,
+// whether its name follows the version scheme, it is accepted by the channel, satisfies
+// the version constraint, if any, is not one of the skipped versions and, when staged,
+// is rolled out to this installation.
+func releaseFilter(
+	provider pvdr.UpdaterProvider,
+	opts Options,
+	skipped []string,
+) (func(*pvdr.Release) bool, error) {
+	scheme := versionScheme(provider, opts)
+
+	allows := func(string) bool { return true }
+	if opts.Constraint != "" {
+		constraint, err := pvdr.ParseConstraint(opts.Constraint)
+		if err != nil {
+			return nil, err
+		}
+		allows = constraint.Allows
+	}
+
+	eligible := rolloutFilter(opts)
+
+	return func(release *pvdr.Release) bool {
+		return validateVersion(provider, opts, release.Name) == nil &&
+			opts.Channel.Accepts(release, scheme) && allows(release.Name) &&
+			!isSkipped(release, skipped, scheme) && eligible(release)
+	}, nil
+}
+
+// rolloutFilter returns a function telling whether this installation is within the
+// rollout of a release. The installation identifier is only looked up when a staged
+// release comes along and, if it is not available, staged releases are not offered.
+func rolloutFilter(opts Options) func(*pvdr.Release) bool {
+	var id string
+	var err error
+	loaded := false
+
+	return func(release *pvdr.Release) bool {
+		if !release.Staged() {
+			return true
+		}
+
+		if !loaded {
+			id, err = installationID(opts)
+			loaded = true
+		}
+
+		return err == nil && release.Eligible(id)
+	}
+}
+
+// validateVersion returns the parse error of version when it doesn't follow the version
+// scheme, so it is never compared lexically with the releases.
+func validateVersion(provider pvdr.UpdaterProvider, opts Options, version string) error {
+	scheme := versionScheme(provider, opts)
+	if scheme == nil {
+		scheme = pvdr.SemVer{}
+	}
+
+	return scheme.Validate(version)
+}
+
+func versionScheme(provider pvdr.UpdaterProvider, opts Options) pvdr.VersionScheme {
+	if opts.Scheme != nil {
+		return opts.Scheme
+	}
+
+	if p, ok := provider.(pvdr.SchemeProvider); ok {
+		return p.VersionScheme()
+	}
+
+	return nil
+}
+
+func filterReleases(releases []*pvdr.Release, accepts func(*pvdr.Release) bool) []*pvdr.Release {
+	accepted := make([]*pvdr.Release, 0, len(releases))
+	for _, release := range releases {
+		if accepts(release) {
+			accepted = append(accepted, release)
+		}
+	}
+
+	return accepted
+}
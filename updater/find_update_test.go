@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"testing"
 
 	pvdr "github.com/aureliano/caravela/provider"
@@ -184,3 +185,417 @@ func TestCheckUpdatesIgnoreCacheError(t *testing.T) {
 	assert.Equal(t, "some error", e.Error())
 	p.AssertCalled(t, "FetchLastRelease", m)
 }
+
+func TestCheckUpdatesInvalidCurrentVersion(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+
+	r, err := FindUpdate(m, p, "latest", true)
+	assert.Nil(t, r)
+	assert.NotNil(t, err)
+	assert.Equal(t, pvdr.SemVer{}.Validate("latest"), err)
+	p.AssertNotCalled(t, "FetchLastRelease", m)
+
+	r, err = FindUpdateWithOptions(m, p, "2024.9.3", true, Options{Scheme: pvdr.Monotonic{}})
+	assert.Nil(t, r)
+	assert.Equal(t, pvdr.Monotonic{}.Validate("2024.9.3"), err)
+}
+
+func TestCheckUpdatesSchemeFetchReleases(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "2024.9.3"}, {Name: "2024.10.1"}, {Name: "2024.06.2"}}, nil,
+	)
+
+	r, err := FindUpdateWithOptions(m, p, "2024.9.3", true, Options{Scheme: pvdr.CalVer{}})
+	assert.Nil(t, err)
+	assert.Equal(t, "2024.10.1", r.Name)
+	p.AssertNotCalled(t, "FetchLastRelease", m)
+}
+
+func TestCheckUpdatesSchemeFetchReleasesError(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("any error"))
+	p.On("FetchReleases", m).Return([]*pvdr.Release(nil), fmt.Errorf("some error"))
+
+	r, err := FindUpdateWithOptions(m, p, "2024.9.3", false, Options{Scheme: pvdr.CalVer{}})
+	assert.Nil(t, r)
+	assert.Equal(t, "some error", err.Error())
+}
+
+func TestCheckUpdatesProviderScheme(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[{"tag_name":"r99"},{"tag_name":"r123"}]`))),
+		}, nil)
+	p := pvdr.GithubProvider{Host: "api.github.com", ProjectPath: "owner/project", Scheme: pvdr.Monotonic{}}
+
+	r, err := FindUpdate(m, p, "r100", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "r123", r.Name)
+}
+
+func TestVersionScheme(t *testing.T) {
+	assert.Nil(t, versionScheme(new(mockProviderFindUpdate), Options{}))
+	assert.Equal(t, pvdr.CalVer{}, versionScheme(new(mockProviderFindUpdate), Options{Scheme: pvdr.CalVer{}}))
+	assert.Equal(t, pvdr.SemVer{}, versionScheme(pvdr.GithubProvider{}, Options{}))
+	assert.Equal(t, pvdr.CalVer{},
+		versionScheme(pvdr.GithubProvider{Scheme: pvdr.Monotonic{}}, Options{Scheme: pvdr.CalVer{}}))
+}
+
+func TestCheckUpdatesStableSkipsPrerelease(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.2.0-rc.1"}, nil)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "v0.2.0-rc.1"}, {Name: "v0.1.3"}, {Name: "v0.1.4", Draft: true}}, nil,
+	)
+
+	r, err := FindUpdate(m, p, "v0.1.2", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.1.3", r.Name)
+	p.AssertCalled(t, "FetchReleases", m)
+}
+
+func TestCheckUpdatesBetaChannel(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.2.0-alpha.1"}, nil)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "v0.2.0-alpha.1"}, {Name: "v0.2.0-beta.1"}, {Name: "v0.1.3"}}, nil,
+	)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.2", true, Options{Channel: pvdr.Beta})
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.2.0-beta.1", r.Name)
+}
+
+func TestCheckUpdatesNightlyChannel(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.2.0-alpha.1"}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.2", true, Options{Channel: pvdr.Nightly})
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.2.0-alpha.1", r.Name)
+	p.AssertNotCalled(t, "FetchReleases", m)
+}
+
+func TestCheckUpdatesCachedPrereleaseNotOffered(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "v0.2.0-rc.1"}, nil)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.2.0-rc.1"}, nil)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v0.2.0-rc.1"}, {Name: "v0.1.3"}}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "v0.1.3"}).Return(nil)
+
+	r, err := FindUpdate(m, p, "v0.1.2", false)
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.1.3", r.Name)
+	p.AssertCalled(t, "CacheRelease", pvdr.Release{Name: "v0.1.3"})
+}
+
+func TestCheckUpdatesNoRelease(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(nil, nil)
+
+	r, err := FindUpdate(m, p, "v0.1.2", true)
+	assert.Nil(t, err)
+	assert.Empty(t, r.Name)
+}
+
+func TestCheckUpdatesConstraint(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v3.0.0"}, nil)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "v3.0.0"}, {Name: "v2.5.1"}, {Name: "v2.6.0-rc.1"}, {Name: "v2.4.0"}}, nil,
+	)
+
+	r, err := FindUpdateWithOptions(m, p, "v2.4.0", true, Options{Constraint: "^2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "v2.5.1", r.Name)
+}
+
+func TestCheckUpdatesCachedReleaseOutOfConstraint(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "v3.0.0"}, nil)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v3.0.0"}, nil)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v3.0.0"}, {Name: "v2.5.1"}, {Name: "v2.4.0"}}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "v2.5.1", CacheKey: "constraint=>=2.3 <3"}).Return(nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v2.4.0", false, Options{Constraint: ">=2.3 <3"})
+	assert.Nil(t, err)
+	assert.Equal(t, "v2.5.1", r.Name)
+}
+
+func TestCheckUpdatesReleaseNotFollowingScheme(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "nightly"}, nil)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "nightly"}, nil)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "1.2.0"}, {Name: "nightly"}, {Name: "1.10.0"}}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "1.10.0"}).Return(nil)
+
+	r, err := FindUpdateWithOptions(m, p, "1.0.0", true, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.10.0", r.Name)
+
+	r, err = FindUpdateWithOptions(m, p, "1.0.0", false, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.10.0", r.Name)
+
+	_, err = FindUpdateWithOptions(m, p, "1.0.0", true, Options{TargetVersion: "nightly"})
+	assert.Equal(t, "release nightly not found", err.Error())
+}
+
+func TestCheckUpdatesCachedForAnotherChannel(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "1.0.0"}, nil)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "1.1.0-rc.1"}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "1.1.0-rc.1", CacheKey: "channel=beta"}).Return(nil)
+
+	r, err := FindUpdateWithOptions(m, p, "1.0.0", false, Options{Channel: pvdr.Beta})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.1.0-rc.1", r.Name)
+	assert.Empty(t, r.CacheKey)
+	p.AssertCalled(t, "CacheRelease", pvdr.Release{Name: "1.1.0-rc.1", CacheKey: "channel=beta"})
+}
+
+func TestCheckUpdatesCachedForSameFilter(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "1.1.0-rc.1", CacheKey: "channel=beta"}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "1.0.0", false, Options{Channel: pvdr.Beta})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.1.0-rc.1", r.Name)
+	assert.Empty(t, r.CacheKey)
+	p.AssertNotCalled(t, "FetchLastRelease", m)
+}
+
+func TestCacheKey(t *testing.T) {
+	assert.Empty(t, cacheKey(Options{}, nil))
+	assert.Equal(t, "channel=nightly;constraint=^2;skip=2.0.1,2.1.0;scheme=provider.CalVer",
+		cacheKey(Options{Channel: pvdr.Nightly, Constraint: "^2", Scheme: pvdr.CalVer{}}, []string{"2.1.0", "2.0.1"}))
+}
+
+func TestCheckUpdatesCachedEmptyRelease(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{CacheKey: "constraint=>=2.3 <3"}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v2.4.0", false, Options{Constraint: ">=2.3 <3"})
+	assert.Nil(t, err)
+	assert.Empty(t, r.Name)
+	p.AssertNotCalled(t, "FetchLastRelease", m)
+}
+
+func TestCheckUpdatesInvalidConstraint(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+
+	r, err := FindUpdateWithOptions(m, p, "v2.4.0", true, Options{Constraint: "^two"})
+	assert.Nil(t, r)
+	assert.Equal(t, `invalid constraint "^two": "two" is not a version`, err.Error())
+	p.AssertNotCalled(t, "FetchLastRelease", m)
+}
+
+func TestCheckUpdatesSkipVersions(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.3.0"}, nil)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "v0.3.0"}, {Name: "v0.2.1"}, {Name: "v0.2.0"}, {Name: "v0.1.0"}}, nil,
+	)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.0", true, Options{SkipVersions: []string{"0.3.0", "0.2.1"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.2.0", r.Name)
+}
+
+func TestCheckUpdatesBlocklist(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	m.On("Do", mock.Anything).Return(blocklistResponse(http.StatusOK, "v0.3.0\n"), nil)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.3.0"}, nil)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v0.3.0"}, {Name: "v0.2.1"}}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.0", true, Options{BlocklistURL: "https://dl.corp.com/blocklist.txt"})
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.2.1", r.Name)
+}
+
+func TestCheckUpdatesBlocklistError(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	m.On("Do", mock.Anything).Return(blocklistResponse(http.StatusInternalServerError, ""), nil)
+	p := new(mockProviderFindUpdate)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.0", true, Options{BlocklistURL: "https://dl.corp.com/blocklist.txt"})
+	assert.Nil(t, r)
+	assert.Equal(t, "blocklist integration error: 500", err.Error())
+	p.AssertNotCalled(t, "FetchLastRelease", m)
+}
+
+func TestCheckUpdatesCachedReleaseSkipped(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "v0.3.0"}, nil)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.3.0"}, nil)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v0.3.0"}, {Name: "v0.2.1"}}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "v0.2.1", CacheKey: "skip=v0.3.0"}).Return(nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.0", false, Options{SkipVersions: []string{"v0.3.0"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.2.1", r.Name)
+	p.AssertCalled(t, "CacheRelease", pvdr.Release{Name: "v0.2.1", CacheKey: "skip=v0.3.0"})
+}
+
+// rolloutInstallationID returns an installation identifier that is or is not
+// within the rollout of release, according to eligible.
+func rolloutInstallationID(release *pvdr.Release, eligible bool) string {
+	for i := 0; ; i++ {
+		id := fmt.Sprintf("installation-%d", i)
+		if release.Eligible(id) == eligible {
+			return id
+		}
+	}
+}
+
+func TestCheckUpdatesRollout(t *testing.T) {
+	staged := &pvdr.Release{Name: "v0.3.0", Rollout: 5}
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(staged, nil)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{staged, {Name: "v0.2.1"}}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.0", true, Options{InstallationID: rolloutInstallationID(staged, true)})
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.3.0", r.Name)
+
+	r, err = FindUpdateWithOptions(m, p, "v0.1.0", true, Options{InstallationID: rolloutInstallationID(staged, false)})
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.2.1", r.Name)
+}
+
+func TestCheckUpdatesRolloutInstallationIDError(t *testing.T) {
+	mpUserConfigDir = func() (string, error) { return "", fmt.Errorf("$HOME is not defined") }
+	defer func() { mpUserConfigDir = os.UserConfigDir }()
+
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.3.0", Rollout: 99}, nil)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v0.3.0", Rollout: 99}, {Name: "v0.2.1"}}, nil)
+
+	r, err := FindUpdate(m, p, "v0.1.0", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.2.1", r.Name)
+}
+
+type lastReleaseProvider struct{ release *pvdr.Release }
+
+func (p lastReleaseProvider) FetchLastRelease(client pvdr.HTTPClientPlugin) (*pvdr.Release, error) {
+	return p.release, nil
+}
+
+func (lastReleaseProvider) CacheRelease(rel pvdr.Release) error {
+	return nil
+}
+
+func (lastReleaseProvider) RestoreCacheRelease() (*pvdr.Release, error) {
+	return nil, fmt.Errorf("no cache")
+}
+
+func TestCheckUpdatesTargetVersion(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "v1.9.0"}, {Name: "v1.8.3"}, {Name: "v1.8.2"}}, nil,
+	)
+
+	r, err := FindUpdateWithOptions(m, p, "v1.8.2", false, Options{TargetVersion: "1.8.3"})
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.8.3", r.Name)
+	p.AssertNotCalled(t, "RestoreCacheRelease")
+	p.AssertNotCalled(t, "FetchLastRelease", m)
+}
+
+func TestCheckUpdatesTargetVersionIgnoresChannel(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v2.0.0-rc.1"}, {Name: "v1.9.0"}}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v1.9.0", true, Options{TargetVersion: "v2.0.0-rc.1", Constraint: "^1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "v2.0.0-rc.1", r.Name)
+}
+
+func TestCheckUpdatesTargetVersionDowngrade(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v1.9.0"}, {Name: "v1.8.3"}}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v1.9.0", true, Options{TargetVersion: "1.8.3"})
+	assert.Nil(t, r)
+	assert.Equal(t, "release v1.8.3 is older than current version v1.9.0 and downgrade is not allowed", err.Error())
+
+	r, err = FindUpdateWithOptions(m, p, "v1.9.0", true, Options{TargetVersion: "1.8.3", AllowDowngrade: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.8.3", r.Name)
+}
+
+func TestCheckUpdatesTargetVersionIsCurrent(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v1.9.0"}}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "1.9.0", true, Options{TargetVersion: "v1.9.0"})
+	assert.Nil(t, err)
+	assert.Empty(t, r.Name)
+}
+
+func TestCheckUpdatesTargetVersionSkipped(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v0.3.0"}, {Name: "v0.2.1"}}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v0.1.0", true, Options{TargetVersion: "0.3.0", SkipVersions: []string{"0.3.0"}})
+	assert.Nil(t, r)
+	assert.Equal(t, "release v0.3.0 has been yanked", err.Error())
+}
+
+func TestCheckUpdatesTargetVersionNotFound(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "v1.9.0"}, {Name: "v1.8.4", Draft: true}}, nil)
+
+	r, err := FindUpdateWithOptions(m, p, "v1.9.0", true, Options{TargetVersion: "1.8.4"})
+	assert.Nil(t, r)
+	assert.Equal(t, "release 1.8.4 not found", err.Error())
+}
+
+func TestCheckUpdatesTargetVersionFetchError(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release(nil), fmt.Errorf("some error"))
+
+	r, err := FindUpdateWithOptions(m, p, "v1.9.0", true, Options{TargetVersion: "1.8.3"})
+	assert.Nil(t, r)
+	assert.Equal(t, "some error", err.Error())
+}
+
+func TestCheckUpdatesTargetVersionNotSupported(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := lastReleaseProvider{release: &pvdr.Release{Name: "v1.9.0"}}
+
+	r, err := FindUpdateWithOptions(m, p, "v1.9.0", true, Options{TargetVersion: "1.8.3"})
+	assert.Nil(t, r)
+	assert.Equal(t, "provider is not able to look up release 1.8.3", err.Error())
+}
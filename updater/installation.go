@@ -0,0 +1,47 @@
+package updater
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const installationIDFileName = "installation-id"
+
+var mpUserConfigDir = os.UserConfigDir
+
+// installationID returns the identifier of this installation, which decides
+// whether it is within the rollout of a release. It is opts.InstallationID,
+// when set, or an identifier generated once and kept in the user configuration
+// directory, so it stays the same across runs.
+func installationID(opts Options) (string, error) {
+	if opts.InstallationID != "" {
+		return opts.InstallationID, nil
+	}
+
+	dir, err := mpUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, "caravela", installationIDFileName)
+	if data, err := os.ReadFile(file); err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	}
+
+	random := make([]byte, 16)
+	if _, err = rand.Read(random); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(random)
+
+	if err = os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return "", err
+	}
+
+	return id, os.WriteFile(file, []byte(id), 0600)
+}
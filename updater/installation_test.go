@@ -0,0 +1,45 @@
+package updater
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallationID(t *testing.T) {
+	dir := t.TempDir()
+	mpUserConfigDir = func() (string, error) { return dir, nil }
+	defer func() { mpUserConfigDir = os.UserConfigDir }()
+
+	id, err := installationID(Options{})
+	assert.Nil(t, err)
+	assert.Len(t, id, 32)
+
+	data, err := os.ReadFile(filepath.Join(dir, "caravela", "installation-id"))
+	assert.Nil(t, err)
+	assert.Equal(t, id, string(data))
+
+	again, err := installationID(Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, id, again)
+}
+
+func TestInstallationIDOption(t *testing.T) {
+	mpUserConfigDir = func() (string, error) { return "", fmt.Errorf("should not be called") }
+	defer func() { mpUserConfigDir = os.UserConfigDir }()
+
+	id, err := installationID(Options{InstallationID: "machine-42"})
+	assert.Nil(t, err)
+	assert.Equal(t, "machine-42", id)
+}
+
+func TestInstallationIDConfigDirError(t *testing.T) {
+	mpUserConfigDir = func() (string, error) { return "", fmt.Errorf("$HOME is not defined") }
+	defer func() { mpUserConfigDir = os.UserConfigDir }()
+
+	_, err := installationID(Options{})
+	assert.Equal(t, "$HOME is not defined", err.Error())
+}
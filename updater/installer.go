@@ -28,7 +28,13 @@ func install(srcDir, destDir string) error {
 			}
 
 			if info.IsDir() {
-				err = os.Mkdir(filepath.Join(destDir, relPath), info.Mode())
+				// Directories are kept from the previous installation.
+				err = os.MkdirAll(filepath.Join(destDir, relPath), info.Mode().Perm())
+				if err != nil {
+					return err
+				}
+			} else if info.Mode()&os.ModeSymlink != 0 {
+				err = installSymlink(filepath.Join(destDir, relPath), path)
 				if err != nil {
 					return err
 				}
@@ -48,6 +54,8 @@ func install(srcDir, destDir string) error {
 	return err
 }
 
+// installFile copies src to dest. A new file gets the mode of src, as extracted
+// from the archive, whereas an existing one keeps its mode.
 func installFile(dest, src string) error {
 	destInfo, err := os.Stat(dest)
 	fileExist := true
@@ -55,8 +63,11 @@ func installFile(dest, src string) error {
 
 	if os.IsNotExist(err) {
 		fileExist = false
-		const permFile = 0644
-		fm = fs.FileMode(permFile)
+		srcInfo, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		fm = srcInfo.Mode().Perm()
 	} else if err != nil {
 		return err
 	}
@@ -87,19 +98,42 @@ func installFile(dest, src string) error {
 	return nil
 }
 
+// installSymlink recreates the symbolic link src as dest, so it keeps pointing
+// to the same relative target.
+func installSymlink(dest, src string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+
+	if err = removeExisting(dest); err != nil {
+		return err
+	}
+
+	return os.Symlink(target, dest)
+}
+
+// installBinary installs the raw binary src as dest, the file of the running
+// executable, with the executable bit set.
+func installBinary(dest, src string) error {
+	if err := installFile(dest, src); err != nil {
+		return err
+	}
+
+	const permExecutable = 0755
+	return os.Chmod(dest, permExecutable)
+}
+
 func shouldIgoreFile(fname string) bool {
 	types := []string{
 		".deb",
 		".zip.sbom",
 		".tar.gz.sbom",
-		".tar.gz",
 		".apk",
-		".tar.zst",
-		".zip",
 		".rpm",
 	}
 
-	if fname == "checksums.txt" {
+	if fname == checksumsFileName || fname == artifactsFileName || isArchive(fname) {
 		return true
 	}
 
@@ -129,6 +129,27 @@ func TestInstallFileNew(t *testing.T) {
 	os.Remove(dest)
 }
 
+func TestInstallFileNewKeepsSourceMode(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "14-bis-helper")
+	if err := os.WriteFile(src, []byte("#!/bin/sh"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "installed")
+	err := installFile(dest, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+}
+
 func TestInstallFileReplace(t *testing.T) {
 	file, err := os.Create(filepath.Join(os.TempDir(), "file.txt"))
 	if err != nil {
@@ -172,6 +193,80 @@ func TestInstallFileReplace(t *testing.T) {
 	os.Remove(dest)
 }
 
+func TestInstallSymlink(t *testing.T) {
+	srcDir := t.TempDir()
+	destDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(srcDir, "14-bis_v0.1.2"), []byte("binary"), 0600)
+	assert.Nil(t, err)
+	err = os.Symlink("14-bis_v0.1.2", filepath.Join(srcDir, "14-bis"))
+	assert.Nil(t, err)
+	err = os.Symlink("14-bis_v0.1.1", filepath.Join(destDir, "14-bis"))
+	assert.Nil(t, err)
+
+	err = install(srcDir, destDir)
+	assert.Nil(t, err, err)
+
+	target, err := os.Readlink(filepath.Join(destDir, "14-bis"))
+	assert.Nil(t, err)
+	assert.Equal(t, "14-bis_v0.1.2", target)
+
+	bytes, err := os.ReadFile(filepath.Join(destDir, "14-bis"))
+	assert.Nil(t, err)
+	assert.Equal(t, "binary", string(bytes))
+}
+
+func TestInstallTwiceWithDirectories(t *testing.T) {
+	srcDir := t.TempDir()
+	destDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(srcDir, "completions"), 0755)
+	assert.Nil(t, err)
+	err = os.WriteFile(filepath.Join(srcDir, "completions", "14-bis.bash"), []byte("v0.1.1"), 0644)
+	assert.Nil(t, err)
+
+	err = install(srcDir, destDir)
+	assert.Nil(t, err, err)
+
+	err = os.WriteFile(filepath.Join(srcDir, "completions", "14-bis.bash"), []byte("v0.1.2"), 0644)
+	assert.Nil(t, err)
+
+	err = install(srcDir, destDir)
+	assert.Nil(t, err, err)
+
+	bytes, err := os.ReadFile(filepath.Join(destDir, "completions", "14-bis.bash"))
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.1.2", string(bytes))
+}
+
+func TestInstallBinary(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "14-bis_linux_amd64")
+	dest := filepath.Join(dir, "14-bis")
+
+	err := os.WriteFile(src, []byte("new"), 0600)
+	assert.Nil(t, err)
+	err = os.WriteFile(dest, []byte("old"), 0600)
+	assert.Nil(t, err)
+
+	err = installBinary(dest, src)
+	assert.Nil(t, err)
+
+	bytes, err := os.ReadFile(dest)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", string(bytes))
+
+	info, err := os.Stat(dest)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestInstallBinarySourceNotFound(t *testing.T) {
+	dir := t.TempDir()
+	err := installBinary(filepath.Join(dir, "14-bis"), filepath.Join(dir, "unknown"))
+	assert.NotNil(t, err)
+}
+
 func TestShouldIgoreFile(t *testing.T) {
 	type testCase struct {
 		name     string
@@ -189,6 +284,11 @@ func TestShouldIgoreFile(t *testing.T) {
 			input:    "checksums.txt",
 			expected: true,
 		},
+		{
+			name:     "should ignore goreleaser artifacts file",
+			input:    "artifacts.json",
+			expected: true,
+		},
 		{
 			name:     "should not ignore file",
 			input:    "file.md",
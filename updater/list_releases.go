@@ -0,0 +1,42 @@
+package updater
+
+import (
+	"fmt"
+
+	pvdr "github.com/aureliano/caravela/provider"
+)
+
+// ListReleases fetches the releases published, e.g. to let users pick a version.
+//
+// It returns the releases offered according to opts.Channel and opts.Constraint,
+// sorted by version, newest first. Providers must implement provider.ReleasesFetcher.
+func ListReleases(
+	client pvdr.HTTPClientPlugin,
+	provider pvdr.UpdaterProvider,
+	opts Options,
+) ([]*pvdr.Release, error) {
+	fetcher, ok := provider.(pvdr.ReleasesFetcher)
+	if !ok {
+		return nil, fmt.Errorf("provider is not able to list releases")
+	}
+
+	skipped, err := skippedVersions(client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	accepts, err := releaseFilter(provider, opts, skipped)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := fetcher.FetchReleases(client)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := filterReleases(releases, accepts)
+	pvdr.SortReleases(accepted, versionScheme(provider, opts))
+
+	return accepted, nil
+}
@@ -0,0 +1,64 @@
+package updater
+
+import (
+	"fmt"
+	"testing"
+
+	pvdr "github.com/aureliano/caravela/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListReleases(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "v0.1.2"}, {Name: "v0.1.10"}, {Name: "v0.2.0-rc.1"}, {Name: "v0.1.9"}}, nil,
+	)
+
+	releases, err := ListReleases(m, p, Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, []*pvdr.Release{{Name: "v0.1.10"}, {Name: "v0.1.9"}, {Name: "v0.1.2"}}, releases)
+
+	releases, err = ListReleases(m, p, Options{Channel: pvdr.Beta, Constraint: ">=0.1.9"})
+	assert.Nil(t, err)
+	assert.Equal(t, []*pvdr.Release{{Name: "v0.2.0-rc.1"}, {Name: "v0.1.10"}, {Name: "v0.1.9"}}, releases)
+
+	releases, err = ListReleases(m, p, Options{SkipVersions: []string{"0.1.10"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []*pvdr.Release{{Name: "v0.1.9"}, {Name: "v0.1.2"}}, releases)
+}
+
+func TestListReleasesScheme(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{{Name: "r9"}, {Name: "r10"}}, nil)
+
+	releases, err := ListReleases(m, p, Options{Scheme: pvdr.Monotonic{}})
+	assert.Nil(t, err)
+	assert.Equal(t, []*pvdr.Release{{Name: "r10"}, {Name: "r9"}}, releases)
+}
+
+func TestListReleasesFetchError(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release(nil), fmt.Errorf("some error"))
+
+	releases, err := ListReleases(m, p, Options{})
+	assert.Nil(t, releases)
+	assert.Equal(t, "some error", err.Error())
+}
+
+func TestListReleasesInvalidConstraint(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+
+	releases, err := ListReleases(m, p, Options{Constraint: "^two"})
+	assert.Nil(t, releases)
+	assert.Equal(t, `invalid constraint "^two": "two" is not a version`, err.Error())
+}
+
+func TestListReleasesNotSupported(t *testing.T) {
+	releases, err := ListReleases(new(mockHTTPClientFindUpdate), lastReleaseProvider{}, Options{})
+	assert.Nil(t, releases)
+	assert.Equal(t, "provider is not able to list releases", err.Error())
+}
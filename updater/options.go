@@ -0,0 +1,68 @@
+package updater
+
+import "github.com/aureliano/caravela/provider"
+
+// Options gathers the optional settings of an update.
+type Options struct {
+	// RewriteURL, when set, is applied to every asset URL (archives, checksums
+	// and the like) right before it is downloaded. Provider API calls are not
+	// affected. See provider.PrefixRewriter for a prefix mapping table.
+	RewriteURL func(url string) string
+
+	// Scheme, when set, is the version scheme used to compare releases in
+	// place of the one of the provider.
+	Scheme provider.VersionScheme
+
+	// Channel tells which releases are offered: only final ones (the default),
+	// beta and release candidate builds as well, or any release.
+	Channel provider.Channel
+
+	// Constraint, when set, is the range of semantic versions a release must
+	// satisfy to be offered, e.g. ^2 or >=2.3 <3. See provider.Constraint.
+	Constraint string
+
+	// TargetVersion, when set, is the version to move to instead of the last
+	// release, e.g. to pin a known good version during an incident.
+	TargetVersion string
+
+	// AllowDowngrade allows TargetVersion to be older than the current version.
+	AllowDowngrade bool
+
+	// SkipVersions are yanked versions that are never offered, e.g. a bad
+	// release whose assets were deleted. The next best release is offered
+	// in their place.
+	SkipVersions []string
+
+	// BlocklistURL, when set, is the URL of a text file listing yanked versions,
+	// one per line, which are skipped as SkipVersions are. Blank lines and lines
+	// starting with # are ignored.
+	BlocklistURL string
+
+	// InstallationID identifies this installation when deciding whether it is
+	// within the rollout of a staged release. When not set, an identifier is
+	// generated once and kept in the user configuration directory.
+	InstallationID string
+
+	// MaxExtractedSize and MaxExtractedFiles bound the content extracted from a
+	// release archive, 1 GiB and 10000 files by default, to fend off archive bombs.
+	MaxExtractedSize  int64
+	MaxExtractedFiles int
+
+	// ChecksumsFile is the name of the release asset listing the checksums of the
+	// release files, checksums.txt by default. It may be a pattern, e.g.
+	// *_checksums.txt for the files goreleaser names after the version.
+	ChecksumsFile string
+
+	// ChecksumAlgorithm is the hash function of the checksums whose format doesn't
+	// tell it, SHA256 by default.
+	ChecksumAlgorithm ChecksumAlgorithm
+
+	// OnVerification, when set, is given the report of the verification of the
+	// release file against its checksum, whether it succeeded or not, e.g. to log
+	// it for auditing.
+	OnVerification func(report VerificationReport)
+
+	// SmokeTest, when set, runs the new executable before it is installed, e.g.
+	// with --version, and the installation is aborted if it fails.
+	SmokeTest *SmokeTest
+}
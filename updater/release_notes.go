@@ -0,0 +1,36 @@
+package updater
+
+import (
+	pvdr "github.com/aureliano/caravela/provider"
+)
+
+// ReleaseNotes fetches the notes of the releases within the interval (currver, target],
+// newest first. When target is empty, the last release offered is taken.
+//
+// Only the releases offered according to opts.Channel and opts.Constraint are
+// gathered, so stable users do not see the notes of pre-releases. It is an error for
+// currver not to follow the version scheme.
+func ReleaseNotes(
+	client pvdr.HTTPClientPlugin,
+	provider pvdr.UpdaterProvider,
+	currver, target string,
+	opts Options,
+) (pvdr.ReleaseNotes, error) {
+	if err := validateVersion(provider, opts, currver); err != nil {
+		return nil, err
+	}
+
+	releases, err := ListReleases(client, provider, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == "" {
+		if len(releases) == 0 {
+			return pvdr.ReleaseNotes{}, nil
+		}
+		target = releases[0].Name
+	}
+
+	return pvdr.CollectReleaseNotes(releases, currver, target, versionScheme(provider, opts)), nil
+}
@@ -0,0 +1,55 @@
+package updater
+
+import (
+	"fmt"
+	"testing"
+
+	pvdr "github.com/aureliano/caravela/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return(
+		[]*pvdr.Release{{Name: "v1.2.0"}, {Name: "v1.6.0"}, {Name: "v1.5.0-rc.1"}, {Name: "v1.3.0"}, {Name: "v1.7.0"}}, nil,
+	)
+
+	notes, err := ReleaseNotes(m, p, "v1.2.0", "v1.6.0", Options{})
+	assert.Nil(t, err)
+	assert.Equal(t, pvdr.ReleaseNotes{{Name: "v1.6.0"}, {Name: "v1.3.0"}}, notes)
+
+	notes, err = ReleaseNotes(m, p, "v1.3.0", "", Options{Channel: pvdr.Beta})
+	assert.Nil(t, err)
+	assert.Equal(t, pvdr.ReleaseNotes{{Name: "v1.7.0"}, {Name: "v1.6.0"}, {Name: "v1.5.0-rc.1"}}, notes)
+}
+
+func TestReleaseNotesNoRelease(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release{}, nil)
+
+	notes, err := ReleaseNotes(m, p, "v1.2.0", "", Options{})
+	assert.Nil(t, err)
+	assert.Empty(t, notes)
+}
+
+func TestReleaseNotesFetchError(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+	p.On("FetchReleases", m).Return([]*pvdr.Release(nil), fmt.Errorf("some error"))
+
+	notes, err := ReleaseNotes(m, p, "v1.2.0", "", Options{})
+	assert.Nil(t, notes)
+	assert.Equal(t, "some error", err.Error())
+}
+
+func TestReleaseNotesInvalidCurrentVersion(t *testing.T) {
+	m := new(mockHTTPClientFindUpdate)
+	p := new(mockProviderFindUpdate)
+
+	notes, err := ReleaseNotes(m, p, "latest", "", Options{})
+	assert.Nil(t, notes)
+	assert.NotNil(t, err)
+	p.AssertNotCalled(t, "FetchReleases", m)
+}
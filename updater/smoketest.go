@@ -0,0 +1,80 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const defaultSmokeTestTimeout = time.Second * 10
+
+// maxSmokeTestOutput is the length of the output of a failed smoke test that is
+// kept in the error.
+const maxSmokeTestOutput = 512
+
+// ErrSmokeTestFailed is reported when the new executable fails its smoke test, so
+// it is not installed.
+var ErrSmokeTestFailed = errors.New("smoke test failed")
+
+// SmokeTest runs the new executable before it replaces the running one, e.g. with
+// --version, so a broken build is never installed. The executable must exit with
+// status 0 within Timeout, 10 seconds by default. When ExpectVersion is set, its
+// output must contain the version of the release as well.
+type SmokeTest struct {
+	Args          []string
+	Timeout       time.Duration
+	ExpectVersion bool
+}
+
+// smokeTest runs the executable at path as told by test. version is the name of
+// the release it comes from, e.g. v1.2.0, which is expected in the output with or
+// without its v prefix.
+func smokeTest(path, version string, test SmokeTest) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSmokeTestFailed, err)
+	}
+
+	// Raw binaries are downloaded without the executable bit.
+	if info.Mode()&0111 == 0 {
+		const permExecutable = 0755
+		if err = os.Chmod(path, permExecutable); err != nil {
+			return err
+		}
+	}
+
+	timeout := test.Timeout
+	if timeout <= 0 {
+		timeout = defaultSmokeTestTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, test.Args...).CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s timed out after %s", ErrSmokeTestFailed, path, timeout)
+	} else if err != nil {
+		return fmt.Errorf("%w: %s: %v: %s", ErrSmokeTestFailed, path, err, truncateOutput(output))
+	}
+
+	if test.ExpectVersion && !strings.Contains(string(output), strings.TrimPrefix(version, "v")) {
+		return fmt.Errorf("%w: %s output doesn't contain version %s: %s",
+			ErrSmokeTestFailed, path, version, truncateOutput(output))
+	}
+
+	return nil
+}
+
+func truncateOutput(output []byte) string {
+	out := strings.TrimSpace(string(output))
+	if len(out) > maxSmokeTestOutput {
+		return out[:maxSmokeTestOutput] + "..."
+	}
+
+	return out
+}
@@ -0,0 +1,87 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createScript(t *testing.T, content string, perm os.FileMode) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+
+	dir, err := os.MkdirTemp("", "test-smoke-*")
+	assert.Nil(t, err, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "14-bis")
+	err = os.WriteFile(path, []byte("#!/bin/sh\n"+content), perm)
+	assert.Nil(t, err, err)
+
+	return path
+}
+
+func TestSmokeTest(t *testing.T) {
+	path := createScript(t, `[ "$1" = "--version" ] && echo "14-bis version 1.2.0"`, 0755)
+
+	err := smokeTest(path, "v1.2.0", SmokeTest{Args: []string{"--version"}, ExpectVersion: true})
+	assert.Nil(t, err, err)
+}
+
+func TestSmokeTestSetsExecutableBit(t *testing.T) {
+	path := createScript(t, "echo 1.2.0", 0644)
+
+	err := smokeTest(path, "1.2.0", SmokeTest{})
+	assert.Nil(t, err, err)
+
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
+func TestSmokeTestExitCode(t *testing.T) {
+	path := createScript(t, "echo 'error while loading shared libraries: libc.so.6' >&2\nexit 127", 0755)
+
+	err := smokeTest(path, "1.2.0", SmokeTest{Args: []string{"--version"}})
+	assert.ErrorIs(t, err, ErrSmokeTestFailed)
+	assert.Contains(t, err.Error(), "exit status 127")
+	assert.Contains(t, err.Error(), "error while loading shared libraries")
+}
+
+func TestSmokeTestVersionNotInOutput(t *testing.T) {
+	path := createScript(t, "echo 14-bis version 1.1.0", 0755)
+
+	err := smokeTest(path, "v1.2.0", SmokeTest{ExpectVersion: true})
+	assert.ErrorIs(t, err, ErrSmokeTestFailed)
+	assert.Contains(t, err.Error(), "output doesn't contain version v1.2.0: 14-bis version 1.1.0")
+
+	err = smokeTest(path, "v1.2.0", SmokeTest{})
+	assert.Nil(t, err, err)
+}
+
+func TestSmokeTestTimeout(t *testing.T) {
+	path := createScript(t, "exec sleep 5", 0755)
+
+	err := smokeTest(path, "1.2.0", SmokeTest{Timeout: time.Millisecond * 100})
+	assert.ErrorIs(t, err, ErrSmokeTestFailed)
+	assert.Contains(t, err.Error(), "timed out after 100ms")
+}
+
+func TestSmokeTestExecutableNotFound(t *testing.T) {
+	err := smokeTest(filepath.Join(os.TempDir(), "no", "14-bis"), "1.2.0", SmokeTest{})
+	assert.ErrorIs(t, err, ErrSmokeTestFailed)
+}
+
+func TestTruncateOutput(t *testing.T) {
+	assert.Equal(t, "1.2.0", truncateOutput([]byte(" 1.2.0\n")))
+
+	long := make([]byte, maxSmokeTestOutput+10)
+	for i := range long {
+		long[i] = 'a'
+	}
+	assert.Len(t, truncateOutput(long), maxSmokeTestOutput+len("..."))
+}
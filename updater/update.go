@@ -1,6 +1,7 @@
 package updater
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,8 @@ var mpDownloadTo = downloadTo
 var mpDecompress = decompress
 var mpChecksum = checksum
 var mpInstall = install
+var mpInstallBinary = installBinary
+var mpSmokeTest = smokeTest
 
 // Update updates running program to the last available release.
 //
@@ -24,7 +27,19 @@ func UpdateRelease(
 	currver string,
 	ignoreCache bool,
 ) (*pvdr.Release, error) {
-	rel, err := FindUpdate(client, provider, currver, ignoreCache)
+	return UpdateReleaseWithOptions(client, provider, currver, ignoreCache, Options{})
+}
+
+// UpdateReleaseWithOptions is UpdateRelease along with the optional settings in
+// opts, which are given to FindUpdateWithOptions as well.
+func UpdateReleaseWithOptions(
+	client pvdr.HTTPClientPlugin,
+	provider pvdr.UpdaterProvider,
+	currver string,
+	ignoreCache bool,
+	opts Options,
+) (*pvdr.Release, error) {
+	rel, err := FindUpdateWithOptions(client, provider, currver, ignoreCache, opts)
 	if err != nil {
 		return nil, err
 	} else if rel.Name == "" {
@@ -36,33 +51,89 @@ func UpdateRelease(
 		return nil, err
 	}
 
+	// Leftovers of a failed run must not be installed along with this release.
 	dir := filepath.Join(os.TempDir(), filepath.Base(procFile))
-	err = os.MkdirAll(dir, os.ModePerm)
-	if err != nil {
+	if err = os.RemoveAll(dir); err != nil {
 		return nil, err
 	}
 
-	bin, checksums, err := mpDownloadTo(client, rel, dir)
+	err = os.MkdirAll(dir, os.ModePerm)
 	if err != nil {
 		return nil, err
 	}
+	defer os.RemoveAll(dir)
 
-	_, err = mpDecompress(bin)
-	if err != nil {
-		return nil, err
+	// Private registries demand the credentials of the provider for the assets too.
+	if downloader, ok := provider.(pvdr.AssetDownloader); ok {
+		client = downloader.DownloadClient(client, rel)
 	}
 
-	err = mpChecksum(bin, checksums)
+	verifier, _ := provider.(pvdr.AssetVerifier)
+	download, err := mpDownloadTo(client, rel, dir, verifier, opts)
 	if err != nil {
+		// A release file not listed in the checksums is reported before downloading it.
+		var checksumErr *ChecksumError
+		if errors.As(err, &checksumErr) && opts.OnVerification != nil {
+			opts.OnVerification(checksumErr.Report)
+		}
+
 		return nil, err
 	}
 
-	err = mpInstall(dir, filepath.Dir(procFile))
+	// The release file is verified before anything is extracted from it.
+	if download.checksums != "" {
+		var report VerificationReport
+		report, err = mpChecksum(download)
+		if opts.OnVerification != nil {
+			opts.OnVerification(report)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A raw binary, as well as a single compressed file, is the executable itself.
+	archive := false
+	executable := download.path
+	if isArchiveFile(download.path) {
+		limits := extractionLimits{maxSize: opts.MaxExtractedSize, maxFiles: opts.MaxExtractedFiles}
+		extracted, err := mpDecompress(download.path, limits)
+		if err != nil {
+			return nil, err
+		}
+
+		if extracted.single != "" {
+			executable = extracted.single
+		} else {
+			archive = true
+			executable = filepath.Join(dir, filepath.Base(procFile))
+		}
+	}
+
+	// The new executable is run before it replaces the running one. Only the file
+	// extracted at the root under the name of the running one replaces it.
+	if opts.SmokeTest != nil {
+		if _, err = os.Lstat(executable); archive && os.IsNotExist(err) {
+			return nil, fmt.Errorf("executable %s not found at the root of %s, so it can't be smoke tested",
+				filepath.Base(executable), filepath.Base(download.path))
+		}
+
+		err = mpSmokeTest(executable, rel.Name, *opts.SmokeTest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if archive {
+		err = mpInstall(dir, filepath.Dir(procFile))
+	} else {
+		err = mpInstallBinary(procFile, executable)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	os.RemoveAll(dir)
-
 	return rel, nil
 }
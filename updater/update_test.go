@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	pvdr "github.com/aureliano/caravela/provider"
@@ -141,9 +144,10 @@ func TestUpdateDownloadFail(t *testing.T) {
 	)
 	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
 	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
-	mpProcessFilePath = func() (string, error) { return "", nil }
-	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string) (string, string, error) {
-		return "", "", fmt.Errorf("download release error")
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{}, fmt.Errorf("download release error")
 	}
 
 	_, err := UpdateRelease(m, p, "0.1.1", false)
@@ -169,11 +173,14 @@ func TestUpdateDecompressionFail(t *testing.T) {
 	)
 	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
 	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
-	mpProcessFilePath = func() (string, error) { return "", nil }
-	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string) (string, string, error) {
-		return "", "", nil
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "14-bis_Linux_x86_64.tar.gz"}, nil
+	}
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		return decompression{}, fmt.Errorf("decompression error")
 	}
-	mpDecompress = func(src string) (int, error) { return 0, fmt.Errorf("decompression error") }
 	_, err := UpdateRelease(m, p, "0.1.1", false)
 
 	p.AssertNotCalled(t, "FetchLastRelease")
@@ -197,18 +204,68 @@ func TestUpdateChecksumFail(t *testing.T) {
 	)
 	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
 	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
-	mpProcessFilePath = func() (string, error) { return "", nil }
-	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string) (string, string, error) {
-		return "", "", nil
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "14-bis_Linux_x86_64.tar.gz", digest: "12345", checksums: "checksums.txt"}, nil
 	}
-	mpDecompress = func(src string) (int, error) { return 1, nil }
-	mpChecksum = func(binPath, checksumsPath string) error { return fmt.Errorf("checksum error") }
-	_, err := UpdateRelease(m, p, "0.1.1", false)
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		return decompression{files: 0}, fmt.Errorf("unverified archive extracted")
+	}
+	mpChecksum = func(download releaseDownload) (VerificationReport, error) {
+		assert.Equal(t, "12345", download.digest)
+		report := VerificationReport{File: "14-bis_Linux_x86_64.tar.gz", Expected: "67890", Actual: "12345"}
+		return report, fmt.Errorf("checksum error")
+	}
+	var reports []VerificationReport
+	opts := Options{OnVerification: func(report VerificationReport) { reports = append(reports, report) }}
+	_, err := UpdateReleaseWithOptions(m, p, "0.1.1", false, opts)
 
 	p.AssertNotCalled(t, "FetchLastRelease")
 	p.AssertNotCalled(t, "CacheRelease")
 	p.AssertCalled(t, "RestoreCacheRelease")
 	assert.Equal(t, "checksum error", err.Error())
+	assert.Len(t, reports, 1)
+	assert.Equal(t, "67890", reports[0].Expected)
+}
+
+func TestUpdateChecksumNotListed(t *testing.T) {
+	m := new(mockHTTPClientUpdate)
+	m.On("Do", mock.Anything).Return(
+		&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+		}, nil)
+	p := new(mockProviderUpdate)
+	p.On("FetchLastRelease", m).Return(
+		&pvdr.Release{
+			Name: "v0.1.2",
+			Assets: []pvdr.Asset{
+				{Name: "14-bis.zip", URL: "http://file.zip", OS: runtime.GOOS},
+				{Name: "checksums.txt", URL: "http://checksums.txt"},
+			},
+		}, nil,
+	)
+	p.On("CacheRelease", mock.Anything).Return(nil)
+	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
+	mpProcessFilePath = func() (string, error) { return filepath.Join(t.TempDir(), "14-bis"), nil }
+	mpDownloadTo = downloadTo
+	mpDownloadFile = func(client pvdr.HTTPClientPlugin, sourceURL, dest string,
+		algorithm ChecksumAlgorithm) (string, error) {
+		return "", writeTestChecksums(dest)
+	}
+	defer func() { mpDownloadFile = downloadFile }()
+	mpInstall = func(srcDir, destDir string) error {
+		return fmt.Errorf("unverified release installed")
+	}
+
+	var reports []VerificationReport
+	opts := Options{OnVerification: func(report VerificationReport) { reports = append(reports, report) }}
+	_, err := UpdateReleaseWithOptions(m, p, "0.1.1", false, opts)
+
+	assert.ErrorIs(t, err, ErrChecksumNotListed)
+	expected := VerificationReport{File: "14-bis.zip", Source: "http://checksums.txt", Algorithm: SHA256}
+	assert.Equal(t, []VerificationReport{expected}, reports)
 }
 
 func TestUpdateInstallationFail(t *testing.T) {
@@ -226,12 +283,15 @@ func TestUpdateInstallationFail(t *testing.T) {
 	)
 	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
 	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
-	mpProcessFilePath = func() (string, error) { return "", nil }
-	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string) (string, string, error) {
-		return "", "", nil
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "14-bis_Linux_x86_64.tar.gz"}, nil
+	}
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		return decompression{files: 1}, nil
 	}
-	mpDecompress = func(src string) (int, error) { return 1, nil }
-	mpChecksum = func(binPath, checksumsPath string) error { return nil }
+	mpChecksum = func(download releaseDownload) (VerificationReport, error) { return VerificationReport{}, nil }
 	mpInstall = func(srcDir, destDir string) error { return fmt.Errorf("installation error") }
 	_, err := UpdateRelease(m, p, "0.1.1", false)
 
@@ -257,11 +317,14 @@ func TestUpdate(t *testing.T) {
 	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
 	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
 	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
-	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string) (string, string, error) {
-		return "", "", nil
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "14-bis_Linux_x86_64.tar.gz"}, nil
 	}
-	mpDecompress = func(src string) (int, error) { return 1, nil }
-	mpChecksum = func(binPath, checksumsPath string) error { return nil }
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		return decompression{files: 1}, nil
+	}
+	mpChecksum = func(download releaseDownload) (VerificationReport, error) { return VerificationReport{}, nil }
 	mpInstall = func(srcDir, destDir string) error { return nil }
 	_, err := UpdateRelease(m, p, "0.1.1", false)
 
@@ -270,3 +333,258 @@ func TestUpdate(t *testing.T) {
 	p.AssertCalled(t, "RestoreCacheRelease")
 	assert.Nil(t, err)
 }
+
+type mockDownloaderProviderUpdate struct {
+	mockProviderUpdate
+	client pvdr.HTTPClientPlugin
+}
+
+func (provider *mockDownloaderProviderUpdate) DownloadClient(
+	pvdr.HTTPClientPlugin,
+	*pvdr.Release,
+) pvdr.HTTPClientPlugin {
+	return provider.client
+}
+
+func TestUpdateDownloadClient(t *testing.T) {
+	m := new(mockHTTPClientUpdate)
+	p := &mockDownloaderProviderUpdate{client: new(mockHTTPClientUpdate)}
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		assert.Same(t, p.client, hcp)
+		return releaseDownload{path: "14-bis_Linux_x86_64.tar.gz"}, nil
+	}
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		return decompression{files: 1}, nil
+	}
+	mpInstall = func(srcDir, destDir string) error { return nil }
+	_, err := UpdateRelease(m, p, "0.1.1", false)
+
+	assert.Nil(t, err, err)
+}
+
+func TestUpdateRawBinary(t *testing.T) {
+	defer func() {
+		mpDecompress = decompress
+		mpInstall = install
+		mpInstallBinary = installBinary
+	}()
+
+	m := new(mockHTTPClientUpdate)
+	p := new(mockProviderUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
+	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "/tmp/test-update/14-bis_linux_amd64", checksums: "/tmp/test-update/checksums.txt"}, nil
+	}
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		return decompression{}, fmt.Errorf("unexpected")
+	}
+	mpChecksum = func(download releaseDownload) (VerificationReport, error) {
+		assert.Equal(t, "/tmp/test-update/14-bis_linux_amd64", download.path)
+		return VerificationReport{Verified: true}, nil
+	}
+	mpInstall = func(srcDir, destDir string) error { return fmt.Errorf("unexpected installation") }
+	mpInstallBinary = func(dest, src string) error {
+		assert.Equal(t, "/tmp/test-update", dest)
+		assert.Equal(t, "/tmp/test-update/14-bis_linux_amd64", src)
+		return nil
+	}
+
+	r, err := UpdateRelease(m, p, "0.1.1", false)
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.1.2", r.Name)
+}
+
+func TestUpdateExtractionLimits(t *testing.T) {
+	defer func() { mpDecompress = decompress }()
+
+	m := new(mockHTTPClientUpdate)
+	p := new(mockProviderUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
+	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "14-bis_Linux_x86_64.tar.gz"}, nil
+	}
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		assert.Equal(t, extractionLimits{maxSize: 1 << 20, maxFiles: 5}, limits)
+		return decompression{}, &ExtractionError{Entry: "../14-bis", Err: ErrOutsideRoot}
+	}
+
+	_, err := UpdateReleaseWithOptions(m, p, "0.1.1", false, Options{MaxExtractedSize: 1 << 20, MaxExtractedFiles: 5})
+	assert.ErrorIs(t, err, ErrOutsideRoot)
+}
+
+func TestUpdateSmokeTestFail(t *testing.T) {
+	defer func() {
+		mpDecompress = decompress
+		mpInstall = install
+		mpSmokeTest = smokeTest
+	}()
+
+	m := new(mockHTTPClientUpdate)
+	p := new(mockProviderUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
+	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
+	mpProcessFilePath = func() (string, error) { return "/opt/14-bis/14-bis", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "14-bis_Linux_x86_64.tar.gz"}, nil
+	}
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		return decompression{files: 1}, os.WriteFile(filepath.Join(os.TempDir(), "14-bis", "14-bis"), []byte("v0.1.2"), 0600)
+	}
+	mpSmokeTest = func(path, version string, test SmokeTest) error {
+		assert.Equal(t, filepath.Join(os.TempDir(), "14-bis", "14-bis"), path)
+		assert.Equal(t, "v0.1.2", version)
+		assert.Equal(t, []string{"--version"}, test.Args)
+		return fmt.Errorf("%w: exit status 2", ErrSmokeTestFailed)
+	}
+	mpInstall = func(srcDir, destDir string) error { return fmt.Errorf("unexpected installation") }
+
+	_, err := UpdateReleaseWithOptions(m, p, "0.1.1", false, Options{SmokeTest: &SmokeTest{Args: []string{"--version"}}})
+	assert.ErrorIs(t, err, ErrSmokeTestFailed)
+}
+
+func TestUpdateSmokeTestExecutableNotFound(t *testing.T) {
+	defer func() {
+		mpDecompress = decompress
+		mpInstall = install
+		mpSmokeTest = smokeTest
+	}()
+
+	m := new(mockHTTPClientUpdate)
+	p := new(mockProviderUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	procFile := filepath.Join(t.TempDir(), "14-bis-smoke")
+	defer os.RemoveAll(filepath.Join(os.TempDir(), "14-bis-smoke"))
+	mpProcessFilePath = func() (string, error) { return procFile, nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: filepath.Join(s, "14-bis_Linux_x86_64.tar.gz")}, nil
+	}
+	// The executable is wrapped in a directory of the archive.
+	mpDecompress = func(src string, limits extractionLimits) (decompression, error) {
+		dir := filepath.Join(filepath.Dir(src), "14-bis_0.1.2")
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return decompression{}, err
+		}
+		return decompression{files: 1}, os.WriteFile(filepath.Join(dir, "14-bis-smoke"), []byte("v0.1.2"), 0600)
+	}
+	mpSmokeTest = func(path, version string, test SmokeTest) error {
+		return fmt.Errorf("unexpected smoke test")
+	}
+	mpInstall = func(srcDir, destDir string) error { return fmt.Errorf("unexpected installation") }
+
+	_, err := UpdateReleaseWithOptions(m, p, "0.1.1", false, Options{SmokeTest: &SmokeTest{}})
+	assert.NotErrorIs(t, err, ErrSmokeTestFailed)
+	assert.Equal(t, "executable 14-bis-smoke not found at the root of 14-bis_Linux_x86_64.tar.gz, "+
+		"so it can't be smoke tested", err.Error())
+}
+
+func TestUpdateSmokeTestRawBinary(t *testing.T) {
+	defer func() {
+		mpInstallBinary = installBinary
+		mpSmokeTest = smokeTest
+	}()
+
+	m := new(mockHTTPClientUpdate)
+	p := new(mockProviderUpdate)
+	p.On("FetchLastRelease", m).Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	p.On("CacheRelease", pvdr.Release{Name: "v0.1.2"}).Return(nil)
+	p.On("RestoreCacheRelease").Return(nil, fmt.Errorf("no file error"))
+	mpProcessFilePath = func() (string, error) { return "/tmp/test-update", nil }
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		return releaseDownload{path: "/tmp/test-update/14-bis_linux_amd64"}, nil
+	}
+
+	var tested []string
+	mpSmokeTest = func(path, version string, test SmokeTest) error {
+		tested = append(tested, path)
+		return nil
+	}
+	mpInstallBinary = func(dest, src string) error { return nil }
+
+	_, err := UpdateReleaseWithOptions(m, p, "0.1.1", false, Options{SmokeTest: &SmokeTest{}})
+	assert.Nil(t, err, err)
+	assert.Equal(t, []string{"/tmp/test-update/14-bis_linux_amd64"}, tested)
+
+	tested = nil
+	_, err = UpdateRelease(m, p, "0.1.1", false)
+	assert.Nil(t, err, err)
+	assert.Empty(t, tested)
+}
+
+func TestUpdateSingleCompressedFile(t *testing.T) {
+	defer func() {
+		mpInstall = install
+		mpInstallBinary = installBinary
+		mpSmokeTest = smokeTest
+	}()
+
+	m := new(mockHTTPClientUpdate)
+	p := new(mockProviderUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	procFile := filepath.Join(t.TempDir(), "14-bis-gz")
+	mpProcessFilePath = func() (string, error) { return procFile, nil }
+	defer os.RemoveAll(filepath.Join(os.TempDir(), "14-bis-gz"))
+
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		src := filepath.Join(s, "tool_linux_amd64.gz")
+		return releaseDownload{path: src}, os.WriteFile(src, compressFile(t, formatGzip, []byte("v0.1.2")), 0600)
+	}
+	mpDecompress = decompress
+
+	var tested []string
+	mpSmokeTest = func(path, version string, test SmokeTest) error {
+		tested = append(tested, path)
+		return nil
+	}
+	mpInstall = func(srcDir, destDir string) error { return fmt.Errorf("unexpected installation") }
+	mpInstallBinary = installBinary
+
+	_, err := UpdateReleaseWithOptions(m, p, "0.1.1", false, Options{SmokeTest: &SmokeTest{}})
+	assert.Nil(t, err, err)
+	assert.Equal(t, []string{filepath.Join(os.TempDir(), "14-bis-gz", "tool_linux_amd64")}, tested)
+
+	data, err := os.ReadFile(procFile)
+	assert.Nil(t, err)
+	assert.Equal(t, "v0.1.2", string(data))
+}
+
+func TestUpdateCleansStagingDir(t *testing.T) {
+	m := new(mockHTTPClientUpdate)
+	p := new(mockProviderUpdate)
+	p.On("RestoreCacheRelease").Return(&pvdr.Release{Name: "v0.1.2"}, nil)
+	mpProcessFilePath = func() (string, error) { return "/opt/14-bis/14-bis-stale", nil }
+
+	dir := filepath.Join(os.TempDir(), "14-bis-stale")
+	defer os.RemoveAll(dir)
+	assert.Nil(t, os.MkdirAll(dir, os.ModePerm))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "leftover"), []byte("rejected"), 0600))
+
+	var staged []os.DirEntry
+	mpDownloadTo = func(hcp pvdr.HTTPClientPlugin, r *pvdr.Release, s string,
+		v pvdr.AssetVerifier, o Options) (releaseDownload, error) {
+		staged, _ = os.ReadDir(s)
+		return releaseDownload{}, fmt.Errorf("download error")
+	}
+
+	_, err := UpdateRelease(m, p, "0.1.1", false)
+	assert.Equal(t, "download error", err.Error())
+	assert.Empty(t, staged)
+
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}